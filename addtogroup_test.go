@@ -11,6 +11,8 @@ import (
 )
 
 func TestAddToGroup(t *testing.T) {
+	// The server keeps what was written, as the client reads the group back to verify its change.
+	var written []byte
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !(r.Method == "GET" || r.Method == "PUT") {
 			t.Fatalf("Wanted GET or PUT but got %s\n", r.Method)
@@ -36,6 +38,9 @@ func TestAddToGroup(t *testing.T) {
 }`
 
 		if r.Method == "GET" {
+			if written != nil {
+				group = string(written)
+			}
 			fmt.Fprintf(w, "%s", group)
 			return
 		}
@@ -62,6 +67,7 @@ func TestAddToGroup(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Not expecting an error but got one: %v\n", err)
 		}
+		written = data
 
 		var repogroup repoGroup
 		if err := json.Unmarshal(data, &repogroup); err != nil {
@@ -70,10 +76,10 @@ func TestAddToGroup(t *testing.T) {
 		if len(repogroup.Data.Repositories) != 2 {
 			t.Fatalf("Want 2 but got %d\n", len(repogroup.Data.Repositories))
 		}
-		if !repoIsInGroup("plat.trnk.trnk679", repogroup) {
+		if !isMember("plat.trnk.trnk679", repogroup) {
 			t.Fatalf("Not expecting true but got false\n")
 		}
-		if !repoIsInGroup("somerepo", repogroup) {
+		if !isMember("somerepo", repogroup) {
 			t.Fatalf("Not expecting true but got false\n")
 		}
 
@@ -0,0 +1,234 @@
+package maventools
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// checksums computes the digests Maven deploys alongside each file, in the order it deploys them.
+type checksums struct {
+	extensions []string
+	hashes     []hash.Hash
+}
+
+func newChecksums() *checksums {
+	c := &checksums{extensions: []string{"md5", "sha1", "sha256", "sha512"}}
+	for _, ext := range c.extensions {
+		c.hashes = append(c.hashes, newHash(ext))
+	}
+	return c
+}
+
+// newHash returns a hash for the checksum files with the given extension.
+func newHash(algorithm string) hash.Hash {
+	switch algorithm {
+	case "md5":
+		return md5.New()
+	case "sha1":
+		return sha1.New()
+	case "sha256":
+		return sha256.New()
+	}
+	return sha512.New()
+}
+
+func (c *checksums) Write(p []byte) (int, error) {
+	for _, h := range c.hashes {
+		h.Write(p)
+	}
+	return len(p), nil
+}
+
+func (c *checksums) reset() {
+	for _, h := range c.hashes {
+		h.Reset()
+	}
+}
+
+// uploadArtifact PUTs the content read from r to the file of the coordinate in the repository whose content is at
+// contentPath, relative to BaseURL, followed by its checksum files, and then adds the version to the artifact's
+// maven-metadata.xml.  The file of a timestamped snapshot version is recorded in the version's maven-metadata.xml too.  The
+// content is streamed; if r is an io.Seeker, a failed PUT can be retried from r's position at the start, otherwise it is not
+// retried.  The integer return value is the HTTP response code of the PUT of the content.
+func (config ClientConfig) uploadArtifact(ctx context.Context, op, contentPath string, c Coordinate, r io.Reader) (int, error) {
+	if err := c.Validate(); err != nil {
+		return 0, err
+	}
+
+	sums := newChecksums()
+	path := contentPath + "/" + c.Path()
+	put := request{op: op, method: "PUT", path: path, stream: io.TeeReader(r, sums), contentType: "application/octet-stream", ok: []int{200, 201, 204}}
+	if seeker, ok := r.(io.Seeker); ok {
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, err
+		}
+		put.rewind = func() error {
+			sums.reset()
+			_, err := seeker.Seek(start, io.SeekStart)
+			return err
+		}
+	}
+	rc, _, err := config.send(ctx, put)
+	if err != nil {
+		return rc, err
+	}
+	if err := config.putChecksums(ctx, op, path, sums); err != nil {
+		return rc, err
+	}
+
+	now := time.Now()
+	if timestampedVersion.MatchString(c.Version) {
+		versionPath := contentPath + "/" + c.VersionPath() + "/maven-metadata.xml"
+		if err := config.updateMetadata(ctx, op, versionPath, func(metadata *Metadata) error { return metadata.AddSnapshot(c, now) }); err != nil {
+			return rc, err
+		}
+	}
+	path = contentPath + "/" + c.ArtifactPath() + "/maven-metadata.xml"
+	return rc, config.updateMetadata(ctx, op, path, func(metadata *Metadata) error {
+		if metadata.GroupID == "" {
+			metadata.GroupID, metadata.ArtifactID = c.GroupID, c.ArtifactID
+		}
+		metadata.AddVersion(c.BaseVersion(), now)
+		return nil
+	})
+}
+
+// putChecksums PUTs a checksum file for each of the digests of the file at path.
+func (config ClientConfig) putChecksums(ctx context.Context, op, path string, sums *checksums) error {
+	for i, ext := range sums.extensions {
+		sum := []byte(hex.EncodeToString(sums.hashes[i].Sum(nil)))
+		if _, _, err := config.send(ctx, request{op: op, method: "PUT", path: path + "." + ext, body: sum, contentType: "text/plain", ok: []int{200, 201, 204}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateMetadata applies change to the maven-metadata.xml at path, relative to BaseURL, starting from empty metadata if there is
+// none, and PUTs the file back with its checksum files.
+func (config ClientConfig) updateMetadata(ctx context.Context, op, path string, change func(*Metadata) error) error {
+	rc, data, err := config.send(ctx, request{op: op, method: "GET", path: path, accept: "application/xml", ok: []int{http.StatusOK, http.StatusNotFound}})
+	if err != nil {
+		return err
+	}
+	var metadata Metadata
+	if rc == http.StatusOK {
+		if metadata, err = ParseMetadata(data); err != nil {
+			return err
+		}
+	}
+	if err := change(&metadata); err != nil {
+		return err
+	}
+
+	data, err = metadata.Marshal()
+	if err != nil {
+		return err
+	}
+	if _, _, err := config.send(ctx, request{op: op, method: "PUT", path: path, body: data, contentType: "application/xml", ok: []int{200, 201, 204}}); err != nil {
+		return err
+	}
+	sums := newChecksums()
+	sums.Write(data)
+	return config.putChecksums(ctx, op, path, sums)
+}
+
+// downloadArtifact writes the file of the coordinate in the repository or group whose content is at contentResourceURI to w
+// and verifies it against the .sha256 and .sha1 checksum files beside it, at least one of which must exist.  The checksum
+// files are read first, so nothing is written when they are missing; a mismatch is reported as a *ChecksumError after the
+// content has been written.  The integer return value is the HTTP response code of the GET of the content.
+func (config ClientConfig) downloadArtifact(ctx context.Context, op, contentResourceURI string, c Coordinate, w io.Writer) (int, error) {
+	if err := c.Validate(); err != nil {
+		return 0, err
+	}
+
+	url := c.URL(contentResourceURI)
+	var algorithms, expected []string
+	var hashes []hash.Hash
+	for _, algorithm := range []string{"sha256", "sha1"} {
+		sum, err := config.checksum(ctx, op, url, algorithm)
+		if err != nil {
+			return 0, err
+		}
+		if sum != "" {
+			algorithms, expected = append(algorithms, algorithm), append(expected, sum)
+			hashes = append(hashes, newHash(algorithm))
+		}
+	}
+	if len(hashes) == 0 {
+		return 0, fmt.Errorf("%s: %s has no .sha256 or .sha1 checksum file", op, url)
+	}
+
+	writers := []io.Writer{w}
+	for _, h := range hashes {
+		writers = append(writers, h)
+	}
+	rc, _, err := config.send(ctx, request{op: op, method: "GET", url: url, accept: "*/*", sink: io.MultiWriter(writers...)})
+	if err != nil {
+		return rc, err
+	}
+	for i, h := range hashes {
+		if actual := hex.EncodeToString(h.Sum(nil)); actual != expected[i] {
+			return rc, &ChecksumError{Op: op, URL: url, Algorithm: algorithms[i], Expected: expected[i], Actual: actual}
+		}
+	}
+	return rc, nil
+}
+
+// checksum returns the hex digest in the checksum file with the given extension beside the file at url, or the empty string if
+// there is none.  Besides the bare digest, the file may hold the digest followed by the file name, as md5sum writes it.
+func (config ClientConfig) checksum(ctx context.Context, op, url, algorithm string) (string, error) {
+	rc, data, err := config.send(ctx, request{op: op, method: "GET", url: url + "." + algorithm, accept: "text/plain", ok: []int{http.StatusOK, http.StatusNotFound}})
+	if err != nil || rc == http.StatusNotFound {
+		return "", err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("%s: %s.%s is empty", op, url, algorithm)
+	}
+	sum := strings.ToLower(fields[0])
+	if _, err := hex.DecodeString(sum); err != nil || len(sum) != 2*newHash(algorithm).Size() {
+		return "", fmt.Errorf("%s: %s.%s does not hold a %s digest", op, url, algorithm, algorithm)
+	}
+	return sum, nil
+}
+
+// resolveSnapshot returns c with its snapshot version, such as 1.0-SNAPSHOT, replaced by the timestamped version of the file
+// last deployed to the repository or group whose content is at contentResourceURI, as its version's maven-metadata.xml gives
+// it.  Other coordinates are returned as they are, without a request.  The integer return value is the HTTP response code of
+// the GET of the metadata.
+func (config ClientConfig) resolveSnapshot(ctx context.Context, op, contentResourceURI string, c Coordinate) (Coordinate, int, error) {
+	if err := c.Validate(); err != nil {
+		return Coordinate{}, 0, err
+	}
+	if !strings.HasSuffix(c.Version, snapshotSuffix) {
+		return c, 0, nil
+	}
+
+	url := strings.TrimSuffix(contentResourceURI, "/") + "/" + c.VersionPath() + "/maven-metadata.xml"
+	rc, data, err := config.send(ctx, request{op: op, method: "GET", url: url, accept: "application/xml"})
+	if err != nil {
+		return Coordinate{}, rc, err
+	}
+	metadata, err := ParseMetadata(data)
+	if err != nil {
+		return Coordinate{}, rc, err
+	}
+	version, ok := metadata.SnapshotVersion(c)
+	if !ok {
+		return Coordinate{}, rc, fmt.Errorf("%s: %s does not give a version of %s", op, url, c)
+	}
+	c.Version = version
+	return c, rc, nil
+}
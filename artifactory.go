@@ -0,0 +1,578 @@
+package maventools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type (
+	// The type put to create a local (hosted) repository.
+	artifactoryLocalRepo struct {
+		Key             RepositoryID `json:"key"`
+		RClass          string       `json:"rclass"`
+		PackageType     string       `json:"packageType"`
+		Description     string       `json:"description,omitempty"`
+		RepoLayoutRef   string       `json:"repoLayoutRef"`
+		HandleReleases  bool         `json:"handleReleases"`
+		HandleSnapshots bool         `json:"handleSnapshots"`
+	}
+
+	// The type retrieved or posted to read or mutate a virtual repository, which is Artifactory's analog of a repository group.
+	artifactoryVirtualRepo struct {
+		Key          GroupID        `json:"key"`
+		RClass       string         `json:"rclass"`
+		PackageType  string         `json:"packageType,omitempty"`
+		Description  string         `json:"description,omitempty"`
+		Repositories []RepositoryID `json:"repositories"`
+	}
+
+	// An entry of the repository list.
+	artifactoryRepoListItem struct {
+		Key         string `json:"key"`
+		Type        string `json:"type"`
+		Description string `json:"description"`
+		URL         string `json:"url"`
+		PackageType string `json:"packageType"`
+	}
+
+	// The policy-related part of a local or remote repository's configuration.
+	artifactoryRepoPolicy struct {
+		HandleReleases  bool `json:"handleReleases"`
+		HandleSnapshots bool `json:"handleSnapshots"`
+	}
+
+	ArtifactoryClient struct {
+		ClientConfig
+	}
+)
+
+// NewArtifactoryClient creates a new Artifactory client implementation on which subsequent service methods are called.  The baseURL typically takes
+// the form http://host:port/artifactory.  username and password are the credentials of an admin user capable of creating and mutating
+// repositories within Artifactory.
+func NewArtifactoryClient(baseURL, username, password string) ArtifactoryClient {
+	return ArtifactoryClient{ClientConfig{BaseURL: baseURL, Username: username, Password: password, HttpClient: &http.Client{}}}
+}
+
+// RepositoryExists checks whether a given repository specified by repositoryID exists.  Depending on its version, Artifactory
+// answers a request for a missing repository with either 400 or 404, both of which are taken to mean the repository does not exist.
+//
+// RepositoryExists uses context.Background internally; to specify the context, use RepositoryExistsContext.
+func (client ArtifactoryClient) RepositoryExists(repositoryID RepositoryID) (bool, error) {
+	return client.RepositoryExistsContext(context.Background(), repositoryID)
+}
+
+// RepositoryExistsContext is like RepositoryExists but uses ctx for its HTTP requests and any pending retries.
+func (client ArtifactoryClient) RepositoryExistsContext(ctx context.Context, repositoryID RepositoryID) (_ bool, err error) {
+	ctx, span := client.startOperation(ctx, "ArtifactoryClient.RepositoryExists", repositoryAttribute(repositoryID))
+	defer func() { span.end(err) }()
+
+	rc, _, err := client.send(ctx, request{op: "ArtifactoryClient.RepositoryExists", method: "GET", path: "/api/repositories/" + string(repositoryID), ok: []int{http.StatusOK, http.StatusBadRequest, http.StatusNotFound}})
+	return rc == http.StatusOK, err
+}
+
+// CreateSnapshotRepository creates a new local Maven SNAPSHOT repository with the given repositoryID.  When error is nil, the integer
+// return value is the underlying HTTP response code.
+//
+// CreateSnapshotRepository uses context.Background internally; to specify the context, use CreateSnapshotRepositoryContext.
+func (client ArtifactoryClient) CreateSnapshotRepository(repositoryID RepositoryID) (int, error) {
+	return client.CreateSnapshotRepositoryContext(context.Background(), repositoryID)
+}
+
+// CreateSnapshotRepositoryContext is like CreateSnapshotRepository but uses ctx for its HTTP requests and any pending retries.
+func (client ArtifactoryClient) CreateSnapshotRepositoryContext(ctx context.Context, repositoryID RepositoryID) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "ArtifactoryClient.CreateSnapshotRepository", repositoryAttribute(repositoryID))
+	defer func() { span.end(err) }()
+
+	return client.createHostedRepository(ctx, "ArtifactoryClient.CreateSnapshotRepository", NewHostedRepositoryOptions(repositoryID, SnapshotPolicy))
+}
+
+// CreateReleaseRepository creates a new local Maven RELEASE repository with the given repositoryID.  When error is nil, the integer
+// return value is the underlying HTTP response code.
+//
+// CreateReleaseRepository uses context.Background internally; to specify the context, use CreateReleaseRepositoryContext.
+func (client ArtifactoryClient) CreateReleaseRepository(repositoryID RepositoryID) (int, error) {
+	return client.CreateReleaseRepositoryContext(context.Background(), repositoryID)
+}
+
+// CreateReleaseRepositoryContext is like CreateReleaseRepository but uses ctx for its HTTP requests and any pending retries.
+func (client ArtifactoryClient) CreateReleaseRepositoryContext(ctx context.Context, repositoryID RepositoryID) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "ArtifactoryClient.CreateReleaseRepository", repositoryAttribute(repositoryID))
+	defer func() { span.end(err) }()
+
+	return client.createHostedRepository(ctx, "ArtifactoryClient.CreateReleaseRepository", NewHostedRepositoryOptions(repositoryID, ReleasePolicy))
+}
+
+// CreateHostedRepository creates a new local Maven repository configured by options.  A display name different from the ID
+// becomes the repository description.  Artifactory governs redeployment through permissions rather than repository
+// configuration, so only AllowWrite is accepted as a write policy; Browseable, Indexable, Exposed and NotFoundCacheTTL have
+// no local repository counterpart and are ignored.  When error is nil, the integer return value is the underlying HTTP
+// response code.
+//
+// CreateHostedRepository uses context.Background internally; to specify the context, use CreateHostedRepositoryContext.
+func (client ArtifactoryClient) CreateHostedRepository(options HostedRepositoryOptions) (int, error) {
+	return client.CreateHostedRepositoryContext(context.Background(), options)
+}
+
+// CreateHostedRepositoryContext is like CreateHostedRepository but uses ctx for its HTTP requests and any pending retries.
+func (client ArtifactoryClient) CreateHostedRepositoryContext(ctx context.Context, options HostedRepositoryOptions) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "ArtifactoryClient.CreateHostedRepository", repositoryAttribute(options.ID))
+	defer func() { span.end(err) }()
+
+	return client.createHostedRepository(ctx, "ArtifactoryClient.CreateHostedRepository", options)
+}
+
+// createHostedRepository creates the hosted repository configured by options, attributing any error to op.
+func (client ArtifactoryClient) createHostedRepository(ctx context.Context, op string, options HostedRepositoryOptions) (int, error) {
+	options, err := options.withDefaults()
+	if err != nil {
+		return 0, err
+	}
+	if options.WritePolicy != AllowWrite {
+		return 0, fmt.Errorf("%s(): write policy %s is not supported by Artifactory\n", op, options.WritePolicy)
+	}
+
+	repo := artifactoryLocalRepo{
+		Key:             options.ID,
+		RClass:          "local",
+		PackageType:     "maven",
+		RepoLayoutRef:   "maven-2-default",
+		HandleReleases:  options.Policy != SnapshotPolicy,
+		HandleSnapshots: options.Policy != ReleasePolicy,
+	}
+	if options.Name != string(options.ID) {
+		repo.Description = options.Name
+	}
+
+	data, err := json.Marshal(&repo)
+	if err != nil {
+		return 0, err
+	}
+
+	rc, _, err := client.send(ctx, request{op: op, method: "PUT", path: "/api/repositories/" + string(options.ID), body: data, contentType: "application/json"})
+	return rc, err
+}
+
+// DeleteRepository deletes the repository with the given repositoryID.
+//
+// DeleteRepository uses context.Background internally; to specify the context, use DeleteRepositoryContext.
+func (client ArtifactoryClient) DeleteRepository(repositoryID RepositoryID) (int, error) {
+	return client.DeleteRepositoryContext(context.Background(), repositoryID)
+}
+
+// DeleteRepositoryContext is like DeleteRepository but uses ctx for its HTTP requests and any pending retries.
+func (client ArtifactoryClient) DeleteRepositoryContext(ctx context.Context, repositoryID RepositoryID) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "ArtifactoryClient.DeleteRepository", repositoryAttribute(repositoryID))
+	defer func() { span.end(err) }()
+
+	return client.deleteRepository(ctx, "ArtifactoryClient.DeleteRepository", repositoryID)
+}
+
+// deleteRepository deletes the repository with the given repositoryID, attributing any error to op.  A repository that does
+// not exist, whether Artifactory says so with a 404 or with a 400 and a message, counts as deleted.
+func (client ArtifactoryClient) deleteRepository(ctx context.Context, op string, repositoryID RepositoryID) (int, error) {
+	rc, _, err := client.send(ctx, request{op: op, method: "DELETE", path: "/api/repositories/" + string(repositoryID), ok: []int{200, 404}})
+	if rc == http.StatusBadRequest && errors.Is(err, ErrNotFound) {
+		return rc, nil
+	}
+	return rc, err
+}
+
+// RepositoryGroup returns a representation of the given virtual repository.
+//
+// RepositoryGroup uses context.Background internally; to specify the context, use RepositoryGroupContext.
+func (client ArtifactoryClient) RepositoryGroup(groupID GroupID) (RepositoryGroup, int, error) {
+	return client.RepositoryGroupContext(context.Background(), groupID)
+}
+
+// RepositoryGroupContext is like RepositoryGroup but uses ctx for its HTTP requests and any pending retries.
+func (client ArtifactoryClient) RepositoryGroupContext(ctx context.Context, groupID GroupID) (_ RepositoryGroup, _ int, err error) {
+	ctx, span := client.startOperation(ctx, "ArtifactoryClient.RepositoryGroup", groupAttribute(groupID))
+	defer func() { span.end(err) }()
+
+	virtualRepo, rc, err := client.virtualRepository(ctx, "ArtifactoryClient.RepositoryGroup", groupID)
+	if err != nil {
+		return RepositoryGroup{}, rc, err
+	}
+	if rc != 200 {
+		return RepositoryGroup{}, rc, nil
+	}
+	return client.canonicalize(virtualRepo), rc, nil
+}
+
+// AddRepositoryToGroup adds the given repository specified by repositoryID to the virtual repository specified by groupID.
+//
+// AddRepositoryToGroup uses context.Background internally; to specify the context, use AddRepositoryToGroupContext.
+func (client ArtifactoryClient) AddRepositoryToGroup(repositoryID RepositoryID, groupID GroupID) (int, error) {
+	return client.AddRepositoryToGroupContext(context.Background(), repositoryID, groupID)
+}
+
+// AddRepositoryToGroupContext is like AddRepositoryToGroup but uses ctx for its HTTP requests and any pending retries.
+func (client ArtifactoryClient) AddRepositoryToGroupContext(ctx context.Context, repositoryID RepositoryID, groupID GroupID) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "ArtifactoryClient.AddRepositoryToGroup", repositoryAttribute(repositoryID), groupAttribute(groupID))
+	defer func() { span.end(err) }()
+
+	rc, err := client.updateGroup(ctx, "ArtifactoryClient.AddRepositoryToGroup", groupID, withMember(repositoryID))
+	if err == nil && rc == 0 {
+		client.logAlreadyInGroup(ctx, "ArtifactoryClient.AddRepositoryToGroup", repositoryID, groupID)
+	}
+	return rc, err
+}
+
+// RemoveRepositoryFromGroup removes the given repository specified by repositoryID from the virtual repository specified by groupID.
+//
+// RemoveRepositoryFromGroup uses context.Background internally; to specify the context, use RemoveRepositoryFromGroupContext.
+func (client ArtifactoryClient) RemoveRepositoryFromGroup(repositoryID RepositoryID, groupID GroupID) (int, error) {
+	return client.RemoveRepositoryFromGroupContext(context.Background(), repositoryID, groupID)
+}
+
+// RemoveRepositoryFromGroupContext is like RemoveRepositoryFromGroup but uses ctx for its HTTP requests and any pending retries.
+func (client ArtifactoryClient) RemoveRepositoryFromGroupContext(ctx context.Context, repositoryID RepositoryID, groupID GroupID) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "ArtifactoryClient.RemoveRepositoryFromGroup", repositoryAttribute(repositoryID), groupAttribute(groupID))
+	defer func() { span.end(err) }()
+
+	return client.updateGroup(ctx, "ArtifactoryClient.RemoveRepositoryFromGroup", groupID, withoutMember(repositoryID))
+}
+
+// AddRepositoriesToGroup adds the repositories specified by repositoryIDs to the virtual repository specified by groupID, in order,
+// with a single POST.  The outcome for each repository is MemberAdded, MemberAlreadyPresent or MemberNotFound.
+//
+// AddRepositoriesToGroup uses context.Background internally; to specify the context, use AddRepositoriesToGroupContext.
+func (client ArtifactoryClient) AddRepositoriesToGroup(repositoryIDs []RepositoryID, groupID GroupID) (map[RepositoryID]MembershipOutcome, int, error) {
+	return client.AddRepositoriesToGroupContext(context.Background(), repositoryIDs, groupID)
+}
+
+// AddRepositoriesToGroupContext is like AddRepositoriesToGroup but uses ctx for its HTTP requests and any pending retries.
+func (client ArtifactoryClient) AddRepositoriesToGroupContext(ctx context.Context, repositoryIDs []RepositoryID, groupID GroupID) (_ map[RepositoryID]MembershipOutcome, _ int, err error) {
+	ctx, span := client.startOperation(ctx, "ArtifactoryClient.AddRepositoriesToGroup", repositoriesAttribute(AttributeRepositoryIDs, repositoryIDs), groupAttribute(groupID))
+	defer func() { span.end(err) }()
+
+	return client.changeGroupMembership(ctx, "ArtifactoryClient.AddRepositoriesToGroup", MembershipChange{Add: repositoryIDs}, groupID)
+}
+
+// RemoveRepositoriesFromGroup removes the repositories specified by repositoryIDs from the virtual repository specified by groupID with
+// a single POST.  The outcome for each repository is MemberRemoved or MemberNotAMember.
+//
+// RemoveRepositoriesFromGroup uses context.Background internally; to specify the context, use RemoveRepositoriesFromGroupContext.
+func (client ArtifactoryClient) RemoveRepositoriesFromGroup(repositoryIDs []RepositoryID, groupID GroupID) (map[RepositoryID]MembershipOutcome, int, error) {
+	return client.RemoveRepositoriesFromGroupContext(context.Background(), repositoryIDs, groupID)
+}
+
+// RemoveRepositoriesFromGroupContext is like RemoveRepositoriesFromGroup but uses ctx for its HTTP requests and any pending retries.
+func (client ArtifactoryClient) RemoveRepositoriesFromGroupContext(ctx context.Context, repositoryIDs []RepositoryID, groupID GroupID) (_ map[RepositoryID]MembershipOutcome, _ int, err error) {
+	ctx, span := client.startOperation(ctx, "ArtifactoryClient.RemoveRepositoriesFromGroup", repositoriesAttribute(AttributeRepositoryIDs, repositoryIDs), groupAttribute(groupID))
+	defer func() { span.end(err) }()
+
+	return client.changeGroupMembership(ctx, "ArtifactoryClient.RemoveRepositoriesFromGroup", MembershipChange{Remove: repositoryIDs}, groupID)
+}
+
+// ChangeGroupMembership applies change to the members of the virtual repository specified by groupID with a single POST, and
+// reports the outcome for each repository named in change.  Nothing is POSTed if the group already reflects change, in
+// which case the integer return value is 0.
+//
+// ChangeGroupMembership uses context.Background internally; to specify the context, use ChangeGroupMembershipContext.
+func (client ArtifactoryClient) ChangeGroupMembership(change MembershipChange, groupID GroupID) (map[RepositoryID]MembershipOutcome, int, error) {
+	return client.ChangeGroupMembershipContext(context.Background(), change, groupID)
+}
+
+// ChangeGroupMembershipContext is like ChangeGroupMembership but uses ctx for its HTTP requests and any pending retries.
+func (client ArtifactoryClient) ChangeGroupMembershipContext(ctx context.Context, change MembershipChange, groupID GroupID) (_ map[RepositoryID]MembershipOutcome, _ int, err error) {
+	ctx, span := client.startOperation(ctx, "ArtifactoryClient.ChangeGroupMembership", repositoriesAttribute(AttributeMembersAdded, change.Add), repositoriesAttribute(AttributeMembersRemoved, change.Remove), groupAttribute(groupID))
+	defer func() { span.end(err) }()
+
+	return client.changeGroupMembership(ctx, "ArtifactoryClient.ChangeGroupMembership", change, groupID)
+}
+
+// changeGroupMembership applies change to the members of the group specified by groupID, attributing any error to op.
+func (client ArtifactoryClient) changeGroupMembership(ctx context.Context, op string, change MembershipChange, groupID GroupID) (map[RepositoryID]MembershipOutcome, int, error) {
+	if err := change.validate(); err != nil {
+		return nil, 0, err
+	}
+	missing, rc, err := client.missingRepositories(ctx, op, change.Add)
+	if err != nil {
+		return nil, rc, err
+	}
+	apply, outcomes, err := change.apply(missing)
+	if err != nil {
+		return nil, 0, err
+	}
+	rc, err = client.updateGroup(ctx, op, groupID, apply)
+	if err != nil {
+		return nil, rc, err
+	}
+	return outcomes, rc, nil
+}
+
+// missingRepositories returns the repositories in ids that are not Maven repositories on the server, attributing any error
+// to op.
+func (client ArtifactoryClient) missingRepositories(ctx context.Context, op string, ids []RepositoryID) ([]RepositoryID, int, error) {
+	if len(ids) == 0 {
+		return nil, 0, nil
+	}
+
+	var list []artifactoryRepoListItem
+	if rc, err := client.getJSON(ctx, op, "/api/repositories?packageType=maven", &list); err != nil {
+		return nil, rc, err
+	}
+	existing := make([]RepositoryID, 0, len(list))
+	for _, item := range list {
+		existing = append(existing, RepositoryID(item.Key))
+	}
+	return missingFrom(ids, existing), 0, nil
+}
+
+// CreateRepositoryGroup creates a new Maven virtual repository configured by options.  A display name different from the ID
+// becomes the repository description.  Virtual repositories are always exposed, so options.Exposed is ignored.  When error is
+// nil, the integer return value is the underlying HTTP response code.
+//
+// CreateRepositoryGroup uses context.Background internally; to specify the context, use CreateRepositoryGroupContext.
+func (client ArtifactoryClient) CreateRepositoryGroup(options RepositoryGroupOptions) (int, error) {
+	return client.CreateRepositoryGroupContext(context.Background(), options)
+}
+
+// CreateRepositoryGroupContext is like CreateRepositoryGroup but uses ctx for its HTTP requests and any pending retries.
+func (client ArtifactoryClient) CreateRepositoryGroupContext(ctx context.Context, options RepositoryGroupOptions) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "ArtifactoryClient.CreateRepositoryGroup", groupAttribute(options.ID))
+	defer func() { span.end(err) }()
+
+	options, err = options.withDefaults()
+	if err != nil {
+		return 0, err
+	}
+
+	virtualRepo := artifactoryVirtualRepo{
+		Key:          options.ID,
+		RClass:       "virtual",
+		PackageType:  "maven",
+		Repositories: make([]RepositoryID, 0),
+	}
+	virtualRepo.Repositories = append(virtualRepo.Repositories, options.Repositories...)
+	if options.Name != string(options.ID) {
+		virtualRepo.Description = options.Name
+	}
+
+	data, err := json.Marshal(&virtualRepo)
+	if err != nil {
+		return 0, err
+	}
+
+	rc, _, err := client.send(ctx, request{op: "ArtifactoryClient.CreateRepositoryGroup", method: "PUT", path: "/api/repositories/" + string(options.ID), body: data, contentType: "application/json"})
+	return rc, err
+}
+
+// DeleteRepositoryGroup deletes the virtual repository with the given groupID.  The repositories it aggregates are left intact.
+//
+// DeleteRepositoryGroup uses context.Background internally; to specify the context, use DeleteRepositoryGroupContext.
+func (client ArtifactoryClient) DeleteRepositoryGroup(groupID GroupID) (int, error) {
+	return client.DeleteRepositoryGroupContext(context.Background(), groupID)
+}
+
+// DeleteRepositoryGroupContext is like DeleteRepositoryGroup but uses ctx for its HTTP requests and any pending retries.
+func (client ArtifactoryClient) DeleteRepositoryGroupContext(ctx context.Context, groupID GroupID) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "ArtifactoryClient.DeleteRepositoryGroup", groupAttribute(groupID))
+	defer func() { span.end(err) }()
+
+	// Artifactory deletes virtual and local repositories through the same resource.
+	return client.deleteRepository(ctx, "ArtifactoryClient.DeleteRepositoryGroup", RepositoryID(groupID))
+}
+
+// ListRepositories returns the local and remote Maven repositories that match filter.  Local repositories are reported as
+// hosted and remote repositories as proxies.  Artifactory does not include policies in its repository list, so the
+// configuration of each repository that passes the ID and type filters is read as well.
+//
+// ListRepositories uses context.Background internally; to specify the context, use ListRepositoriesContext.
+func (client ArtifactoryClient) ListRepositories(filter RepositoryFilter) ([]Repository, int, error) {
+	return client.ListRepositoriesContext(context.Background(), filter)
+}
+
+// ListRepositoriesContext is like ListRepositories but uses ctx for its HTTP requests and any pending retries.
+func (client ArtifactoryClient) ListRepositoriesContext(ctx context.Context, filter RepositoryFilter) (_ []Repository, _ int, err error) {
+	ctx, span := client.startOperation(ctx, "ArtifactoryClient.ListRepositories")
+	defer func() { span.end(err) }()
+
+	var list []artifactoryRepoListItem
+	rc, err := client.getJSON(ctx, "ArtifactoryClient.ListRepositories", "/api/repositories?packageType=maven", &list)
+	if err != nil {
+		return nil, rc, err
+	}
+
+	repositories := make([]Repository, 0)
+	for _, item := range list {
+		var repoType RepositoryType
+		switch strings.ToUpper(item.Type) {
+		case "LOCAL", "FEDERATED":
+			repoType = HostedType
+		case "REMOTE":
+			repoType = ProxyType
+		default:
+			continue
+		}
+
+		repo := Repository{
+			ID:                 RepositoryID(item.Key),
+			Name:               item.Key,
+			ResourceURI:        client.BaseURL + "/api/repositories/" + item.Key,
+			Type:               repoType,
+			Format:             strings.ToLower(item.PackageType),
+			ContentResourceURI: client.BaseURL + "/" + item.Key,
+		}
+		if (filter.Type != "" && repo.Type != filter.Type) || !filter.matchesID(item.Key) {
+			continue
+		}
+
+		var policy artifactoryRepoPolicy
+		if rc, err := client.getJSON(ctx, "ArtifactoryClient.ListRepositories", "/api/repositories/"+item.Key, &policy); err != nil {
+			return nil, rc, err
+		}
+		switch {
+		case policy.HandleReleases && policy.HandleSnapshots:
+			repo.Policy = MixedPolicy
+		case policy.HandleReleases:
+			repo.Policy = ReleasePolicy
+		case policy.HandleSnapshots:
+			repo.Policy = SnapshotPolicy
+		}
+
+		if filter.matchesRepository(repo) {
+			repositories = append(repositories, repo)
+		}
+	}
+	return repositories, rc, nil
+}
+
+// ListRepositoryGroups returns the Maven virtual repositories that match filter.  The list Artifactory returns does not
+// include members, so each matching virtual repository is read as well.
+//
+// ListRepositoryGroups uses context.Background internally; to specify the context, use ListRepositoryGroupsContext.
+func (client ArtifactoryClient) ListRepositoryGroups(filter RepositoryFilter) ([]RepositoryGroup, int, error) {
+	return client.ListRepositoryGroupsContext(context.Background(), filter)
+}
+
+// ListRepositoryGroupsContext is like ListRepositoryGroups but uses ctx for its HTTP requests and any pending retries.
+func (client ArtifactoryClient) ListRepositoryGroupsContext(ctx context.Context, filter RepositoryFilter) (_ []RepositoryGroup, _ int, err error) {
+	ctx, span := client.startOperation(ctx, "ArtifactoryClient.ListRepositoryGroups")
+	defer func() { span.end(err) }()
+
+	var list []artifactoryRepoListItem
+	rc, err := client.getJSON(ctx, "ArtifactoryClient.ListRepositoryGroups", "/api/repositories?type=virtual&packageType=maven", &list)
+	if err != nil {
+		return nil, rc, err
+	}
+
+	groups := make([]RepositoryGroup, 0)
+	for _, item := range list {
+		if !filter.matchesID(item.Key) {
+			continue
+		}
+		virtualRepo, rc, err := client.virtualRepository(ctx, "ArtifactoryClient.ListRepositoryGroups", GroupID(item.Key))
+		if err != nil {
+			return nil, rc, err
+		}
+		groups = append(groups, client.canonicalize(virtualRepo))
+	}
+	return groups, rc, nil
+}
+
+// UploadArtifact deploys to the content of an Artifactory repository at /<key>; see IClient.UploadArtifact.
+//
+// UploadArtifact uses context.Background internally; to specify the context, use UploadArtifactContext.
+func (client ArtifactoryClient) UploadArtifact(repositoryID RepositoryID, coordinate Coordinate, r io.Reader) (int, error) {
+	return client.UploadArtifactContext(context.Background(), repositoryID, coordinate, r)
+}
+
+// UploadArtifactContext is like UploadArtifact but uses ctx for its HTTP requests and any pending retries.
+func (client ArtifactoryClient) UploadArtifactContext(ctx context.Context, repositoryID RepositoryID, coordinate Coordinate, r io.Reader) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "ArtifactoryClient.UploadArtifact", repositoryAttribute(repositoryID), coordinateAttribute(coordinate))
+	defer func() { span.end(err) }()
+
+	return client.uploadArtifact(ctx, "ArtifactoryClient.UploadArtifact", "/"+string(repositoryID), coordinate, r)
+}
+
+// DownloadArtifact reads an Artifactory content URI, such as BaseURL/<key>; see IClient.DownloadArtifact.
+//
+// DownloadArtifact uses context.Background internally; to specify the context, use DownloadArtifactContext.
+func (client ArtifactoryClient) DownloadArtifact(contentResourceURI string, coordinate Coordinate, w io.Writer) (int, error) {
+	return client.DownloadArtifactContext(context.Background(), contentResourceURI, coordinate, w)
+}
+
+// DownloadArtifactContext is like DownloadArtifact but uses ctx for its HTTP requests and any pending retries.
+func (client ArtifactoryClient) DownloadArtifactContext(ctx context.Context, contentResourceURI string, coordinate Coordinate, w io.Writer) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "ArtifactoryClient.DownloadArtifact", coordinateAttribute(coordinate))
+	defer func() { span.end(err) }()
+
+	return client.downloadArtifact(ctx, "ArtifactoryClient.DownloadArtifact", contentResourceURI, coordinate, w)
+}
+
+// ResolveSnapshot reads the metadata under an Artifactory content URI; see IClient.ResolveSnapshot.
+//
+// ResolveSnapshot uses context.Background internally; to specify the context, use ResolveSnapshotContext.
+func (client ArtifactoryClient) ResolveSnapshot(contentResourceURI string, coordinate Coordinate) (Coordinate, int, error) {
+	return client.ResolveSnapshotContext(context.Background(), contentResourceURI, coordinate)
+}
+
+// ResolveSnapshotContext is like ResolveSnapshot but uses ctx for its HTTP requests and any pending retries.
+func (client ArtifactoryClient) ResolveSnapshotContext(ctx context.Context, contentResourceURI string, coordinate Coordinate) (_ Coordinate, _ int, err error) {
+	ctx, span := client.startOperation(ctx, "ArtifactoryClient.ResolveSnapshot", coordinateAttribute(coordinate))
+	defer func() { span.end(err) }()
+
+	return client.resolveSnapshot(ctx, "ArtifactoryClient.ResolveSnapshot", contentResourceURI, coordinate)
+}
+
+// virtualRepository retrieves the virtual repository specified by groupID, attributing any error to op.
+func (client ArtifactoryClient) virtualRepository(ctx context.Context, op string, groupID GroupID) (artifactoryVirtualRepo, int, error) {
+	var virtualRepo artifactoryVirtualRepo
+	responseCode, err := client.getJSON(ctx, op, "/api/repositories/"+string(groupID), &virtualRepo)
+	if err != nil {
+		return artifactoryVirtualRepo{}, responseCode, err
+	}
+	if virtualRepo.RClass != "virtual" {
+		return artifactoryVirtualRepo{}, responseCode, fmt.Errorf("%s(): %v is a %s repository, not a virtual repository\n", op, groupID, virtualRepo.RClass)
+	}
+	return virtualRepo, responseCode, nil
+}
+
+// updateGroup applies change to the members of the virtual repository specified by groupID; see updateGroupMembers.
+func (client ArtifactoryClient) updateGroup(ctx context.Context, op string, groupID GroupID, change func([]RepositoryID) ([]RepositoryID, error)) (int, error) {
+	var virtualRepo artifactoryVirtualRepo
+	read := func() ([]RepositoryID, int, error) {
+		var rc int
+		var err error
+		virtualRepo, rc, err = client.virtualRepository(ctx, op, groupID)
+		return virtualRepo.Repositories, rc, err
+	}
+	write := func(members []RepositoryID) (int, error) {
+		virtualRepo.Repositories = members
+		return client.postVirtualRepository(ctx, op, groupID, virtualRepo)
+	}
+	return client.updateGroupMembers(ctx, op, groupID, read, write, change)
+}
+
+// postVirtualRepository writes virtualRepo back to the server.
+func (client ArtifactoryClient) postVirtualRepository(ctx context.Context, op string, groupID GroupID, virtualRepo artifactoryVirtualRepo) (int, error) {
+	data, err := json.Marshal(&virtualRepo)
+	if err != nil {
+		return 0, err
+	}
+
+	// The POST replaces the repository's configuration, so repeating it is harmless.
+	rc, _, err := client.send(ctx, request{op: op, method: "POST", path: "/api/repositories/" + string(groupID), body: data, contentType: "application/json", idempotent: true})
+	return rc, err
+}
+
+func (client ArtifactoryClient) canonicalize(virtualRepo artifactoryVirtualRepo) RepositoryGroup {
+	c := RepositoryGroup{
+		ID:                 virtualRepo.Key,
+		Name:               string(virtualRepo.Key),
+		Format:             strings.ToLower(virtualRepo.PackageType),
+		ContentResourceURI: client.BaseURL + "/" + string(virtualRepo.Key),
+		Repositories:       make([]Repository, 0),
+	}
+	for _, r := range virtualRepo.Repositories {
+		c.Repositories = append(c.Repositories, Repository{ID: r, Name: string(r), ResourceURI: client.BaseURL + "/api/repositories/" + string(r)})
+	}
+	return c
+}
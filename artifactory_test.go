@@ -0,0 +1,546 @@
+package maventools
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const virtualRepo = `{
+   "key" : "libs-snapshot",
+   "rclass" : "virtual",
+   "packageType" : "maven",
+   "description" : "Snapshot builds",
+   "repositories" : [ "plat.trnk.trnk679" ]
+}`
+
+func TestArtifactoryCreateRepo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Fatalf("Wanted PUT but got %s\n", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/api/repositories/somerepo") {
+			t.Fatalf("Wanted URL suffix /api/repositories/somerepo but got: %s\n", r.URL.Path)
+		}
+		if r.Header.Get("Accept") != "application/json" {
+			t.Fatalf("Wanted application/json but got %s for Accept header", r.Header.Get("Accept"))
+		}
+		if r.Header.Get("Content-type") != "application/json" {
+			t.Fatalf("Wanted application/json but got %s for Content-type header", r.Header.Get("Content-type"))
+		}
+
+		authHeader := r.Header.Get("Authorization")
+		if authHeader == "" {
+			t.Fatalf("Wanted an Authorization header but found none")
+		}
+		base64 := authHeader[len("Basic "):]
+		if base64 != "dXNlcjpwYXNzd29yZA==" {
+			t.Fatalf("Wanted dXNlcjpwYXNzd29yZA== but got %s\n", base64)
+		}
+
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("Got an error but was not expecting one: %v\n", err)
+		}
+
+		var repo artifactoryLocalRepo
+		if err := json.Unmarshal(b, &repo); err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+
+		if repo.Key != "somerepo" {
+			t.Fatalf("Want somerepo but got %v\n", repo.Key)
+		}
+		if repo.RClass != "local" {
+			t.Fatalf("Want local but got %v\n", repo.RClass)
+		}
+		if repo.PackageType != "maven" {
+			t.Fatalf("Want maven but got %v\n", repo.PackageType)
+		}
+		if repo.HandleReleases {
+			t.Fatalf("Want false but got true\n")
+		}
+		if !repo.HandleSnapshots {
+			t.Fatalf("Want true but got false\n")
+		}
+
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	client := NewArtifactoryClient(server.URL, "user", "password")
+	i, err := client.CreateSnapshotRepository("somerepo")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if i != 200 {
+		t.Fatalf("Want 200 but got %d\n", i)
+	}
+}
+
+func TestArtifactoryCreateRepoWithError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(400)
+	}))
+	defer server.Close()
+
+	client := NewArtifactoryClient(server.URL, "user", "password")
+	i, err := client.CreateSnapshotRepository("somerepo")
+	if err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+	if i != 400 {
+		t.Fatalf("Want 400 but got %d\n", i)
+	}
+}
+
+func TestArtifactoryRepoExists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("Wanted GET but got %s\n", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/api/repositories/somerepo") {
+			t.Fatalf("Wanted URL suffix /api/repositories/somerepo but got: %s\n", r.URL.Path)
+		}
+		fmt.Fprintf(w, `{"key": "somerepo", "rclass": "local"}`)
+	}))
+	defer server.Close()
+
+	client := NewArtifactoryClient(server.URL, "user", "password")
+	exists, err := client.RepositoryExists("somerepo")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if !exists {
+		t.Fatalf("Wanted true but got false")
+	}
+}
+
+func TestArtifactoryRepoNotExists(t *testing.T) {
+	for _, status := range []int{400, 404} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+
+		client := NewArtifactoryClient(server.URL, "user", "password")
+		exists, err := client.RepositoryExists("somerepo")
+		if err != nil {
+			t.Fatalf("Expecting no error but got one: %v\n", err)
+		}
+		if exists {
+			t.Fatalf("Wanted false but got true for status %d", status)
+		}
+		server.Close()
+	}
+}
+
+func TestArtifactoryDeleteRepo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Fatalf("Wanted DELETE but got %s\n", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/api/repositories/somerepo") {
+			t.Fatalf("Wanted URL suffix /api/repositories/somerepo but got: %s\n", r.URL.Path)
+		}
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	client := NewArtifactoryClient(server.URL, "user", "password")
+	rc, err := client.DeleteRepository("somerepo")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 200 {
+		t.Fatalf("Want 200 but got %d\n", rc)
+	}
+}
+
+func TestArtifactoryDeleteMissingRepo(t *testing.T) {
+	var tests = []struct {
+		status int
+		body   string
+		ok     bool
+	}{
+		{404, "", true},
+		{400, `{"errors":[{"status":400,"message":"Repository somerepo does not exist"}]}`, true},
+		{400, `{"errors":[{"status":400,"message":"Unsupported repository configuration"}]}`, false},
+		{400, "", false},
+	}
+	for _, test := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(test.status)
+			fmt.Fprint(w, test.body)
+		}))
+
+		client := NewArtifactoryClient(server.URL, "user", "password")
+		rc, err := client.DeleteRepository("somerepo")
+		server.Close()
+		if rc != test.status {
+			t.Fatalf("Want %d but got %d\n", test.status, rc)
+		}
+		if test.ok && err != nil {
+			t.Fatalf("Expecting no error for %d %s but got one: %v\n", test.status, test.body, err)
+		}
+		var statusErr *StatusError
+		if !test.ok && !errors.As(err, &statusErr) {
+			t.Fatalf("Want a *StatusError for %d %s but got %v\n", test.status, test.body, err)
+		}
+	}
+}
+
+func TestArtifactoryRepositoryGroup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("Wanted GET but got %s\n", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/api/repositories/libs-snapshot") {
+			t.Fatalf("Wanted URL suffix /api/repositories/libs-snapshot but got: %s\n", r.URL.Path)
+		}
+		fmt.Fprintf(w, "%s", virtualRepo)
+	}))
+	defer server.Close()
+
+	client := NewArtifactoryClient(server.URL, "user", "password")
+	group, rc, err := client.RepositoryGroup("libs-snapshot")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 200 {
+		t.Fatalf("Want 200 but got %d\n", rc)
+	}
+	if group.ID != "libs-snapshot" {
+		t.Fatalf("Want libs-snapshot but got %v\n", group.ID)
+	}
+	if group.ContentResourceURI != server.URL+"/libs-snapshot" {
+		t.Fatalf("Want %s/libs-snapshot but got %v\n", server.URL, group.ContentResourceURI)
+	}
+	if len(group.Repositories) != 1 {
+		t.Fatalf("Want 1 but got %d\n", len(group.Repositories))
+	}
+	if group.Repositories[0].ID != "plat.trnk.trnk679" {
+		t.Fatalf("Want plat.trnk.trnk679 but got %v\n", group.Repositories[0].ID)
+	}
+	if group.Repositories[0].ResourceURI != server.URL+"/api/repositories/plat.trnk.trnk679" {
+		t.Fatalf("Want %s/api/repositories/plat.trnk.trnk679 but got %v\n", server.URL, group.Repositories[0].ResourceURI)
+	}
+}
+
+func TestArtifactoryRepositoryGroupNotVirtual(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"key": "libs-snapshot", "rclass": "local"}`)
+	}))
+	defer server.Close()
+
+	client := NewArtifactoryClient(server.URL, "user", "password")
+	if _, _, err := client.RepositoryGroup("libs-snapshot"); err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+}
+
+func TestArtifactoryAddToGroup(t *testing.T) {
+	var written []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			if written != nil {
+				fmt.Fprintf(w, "%s", written)
+				return
+			}
+			fmt.Fprintf(w, "%s", virtualRepo)
+			return
+		}
+		if r.Method != "POST" {
+			t.Fatalf("Wanted GET or POST but got %s\n", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/api/repositories/libs-snapshot") {
+			t.Fatalf("Wanted URL suffix /api/repositories/libs-snapshot but got: %s\n", r.URL.Path)
+		}
+		if r.Header.Get("Content-type") != "application/json" {
+			t.Fatalf("Wanted application/json but got %s for Content-type header", r.Header.Get("Content-type"))
+		}
+
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+		written = data
+		var repo artifactoryVirtualRepo
+		if err := json.Unmarshal(data, &repo); err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+		if len(repo.Repositories) != 2 {
+			t.Fatalf("Want 2 but got %d\n", len(repo.Repositories))
+		}
+		if repo.Repositories[0] != "plat.trnk.trnk679" {
+			t.Fatalf("Want plat.trnk.trnk679 but got %v\n", repo.Repositories[0])
+		}
+		if repo.Repositories[1] != "somerepo" {
+			t.Fatalf("Want somerepo but got %v\n", repo.Repositories[1])
+		}
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	client := NewArtifactoryClient(server.URL, "user", "password")
+	rc, err := client.AddRepositoryToGroup("somerepo", "libs-snapshot")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 200 {
+		t.Fatalf("Want 200 but got %d\n", rc)
+	}
+}
+
+func TestArtifactoryRemoveFromGroup(t *testing.T) {
+	var written []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			if written != nil {
+				fmt.Fprintf(w, "%s", written)
+				return
+			}
+			fmt.Fprintf(w, "%s", virtualRepo)
+			return
+		}
+		if r.Method != "POST" {
+			t.Fatalf("Wanted GET or POST but got %s\n", r.Method)
+		}
+
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+		written = data
+		var repo artifactoryVirtualRepo
+		if err := json.Unmarshal(data, &repo); err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+		if len(repo.Repositories) != 0 {
+			t.Fatalf("Want 0 but got %d\n", len(repo.Repositories))
+		}
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	client := NewArtifactoryClient(server.URL, "user", "password")
+	rc, err := client.RemoveRepositoryFromGroup("plat.trnk.trnk679", "libs-snapshot")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 200 {
+		t.Fatalf("Want 200 but got %d\n", rc)
+	}
+}
+
+func TestArtifactoryCreateHostedRepo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("Got an error but was not expecting one: %v\n", err)
+		}
+
+		var repo artifactoryLocalRepo
+		if err := json.Unmarshal(b, &repo); err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+		if !repo.HandleReleases || !repo.HandleSnapshots {
+			t.Fatalf("Want releases and snapshots handled but got %v and %v\n", repo.HandleReleases, repo.HandleSnapshots)
+		}
+		if repo.Description != "Team Builds" {
+			t.Fatalf("Want Team Builds but got %v\n", repo.Description)
+		}
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	options := NewHostedRepositoryOptions("somerepo", MixedPolicy)
+	options.Name = "Team Builds"
+
+	client := NewArtifactoryClient(server.URL, "user", "password")
+	if _, err := client.CreateHostedRepository(options); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+}
+
+func TestArtifactoryCreateHostedRepoWriteOnce(t *testing.T) {
+	options := NewHostedRepositoryOptions("somerepo", ReleasePolicy)
+	options.WritePolicy = AllowWriteOnce
+
+	client := NewArtifactoryClient("http://localhost:0", "user", "password")
+	if _, err := client.CreateHostedRepository(options); err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+}
+
+func TestArtifactoryCreateGroup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Fatalf("Wanted PUT but got %s\n", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/api/repositories/libs-snapshot") {
+			t.Fatalf("Wanted URL suffix /api/repositories/libs-snapshot but got: %s\n", r.URL.Path)
+		}
+
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+		var repo artifactoryVirtualRepo
+		if err := json.Unmarshal(data, &repo); err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+		if repo.RClass != "virtual" {
+			t.Fatalf("Want virtual but got %v\n", repo.RClass)
+		}
+		if len(repo.Repositories) != 2 || repo.Repositories[0] != "releases" || repo.Repositories[1] != "snapshots" {
+			t.Fatalf("Want [releases snapshots] but got %v\n", repo.Repositories)
+		}
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	client := NewArtifactoryClient(server.URL, "user", "password")
+	rc, err := client.CreateRepositoryGroup(NewRepositoryGroupOptions("libs-snapshot", "releases", "snapshots"))
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 200 {
+		t.Fatalf("Want 200 but got %d\n", rc)
+	}
+}
+
+func TestArtifactoryListRepositories(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/repositories":
+			if r.URL.Query().Get("packageType") != "maven" {
+				t.Fatalf("Wanted packageType=maven but got %s\n", r.URL.RawQuery)
+			}
+			fmt.Fprintf(w, `[
+  {"key": "libs-release-local", "type": "LOCAL", "packageType": "Maven"},
+  {"key": "plat.trnk.trnk679", "type": "LOCAL", "packageType": "Maven"},
+  {"key": "jcenter", "type": "REMOTE", "packageType": "Maven"},
+  {"key": "libs-snapshot", "type": "VIRTUAL", "packageType": "Maven"}
+]`)
+		case "/api/repositories/libs-release-local", "/api/repositories/jcenter":
+			fmt.Fprintf(w, `{"handleReleases": true, "handleSnapshots": false}`)
+		case "/api/repositories/plat.trnk.trnk679":
+			fmt.Fprintf(w, `{"handleReleases": false, "handleSnapshots": true}`)
+		default:
+			t.Fatalf("Unexpected request for %s\n", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewArtifactoryClient(server.URL, "user", "password")
+	repos, _, err := client.ListRepositories(RepositoryFilter{Type: HostedType})
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if len(repos) != 2 {
+		t.Fatalf("Want 2 but got %d\n", len(repos))
+	}
+	if repos[0].Policy != ReleasePolicy || repos[1].Policy != SnapshotPolicy {
+		t.Fatalf("Want RELEASE and SNAPSHOT but got %v and %v\n", repos[0].Policy, repos[1].Policy)
+	}
+	if repos[1].ContentResourceURI != server.URL+"/plat.trnk.trnk679" {
+		t.Fatalf("Want %s/plat.trnk.trnk679 but got %v\n", server.URL, repos[1].ContentResourceURI)
+	}
+
+	repos, _, err = client.ListRepositories(RepositoryFilter{Policy: ReleasePolicy})
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if len(repos) != 2 || repos[0].ID != "libs-release-local" || repos[1].ID != "jcenter" {
+		t.Fatalf("Want [libs-release-local jcenter] but got %v\n", repos)
+	}
+	if repos[1].Type != ProxyType {
+		t.Fatalf("Want proxy but got %v\n", repos[1].Type)
+	}
+}
+
+func TestArtifactoryListRepositoryGroups(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/repositories":
+			if r.URL.Query().Get("type") != "virtual" {
+				t.Fatalf("Wanted type=virtual but got %s\n", r.URL.RawQuery)
+			}
+			fmt.Fprintf(w, `[{"key": "libs-snapshot", "type": "VIRTUAL", "packageType": "Maven"}]`)
+		case "/api/repositories/libs-snapshot":
+			fmt.Fprintf(w, "%s", virtualRepo)
+		default:
+			t.Fatalf("Unexpected request for %s\n", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewArtifactoryClient(server.URL, "user", "password")
+	groups, _, err := client.ListRepositoryGroups(RepositoryFilter{})
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("Want 1 but got %d\n", len(groups))
+	}
+	if len(groups[0].Repositories) != 1 || groups[0].Repositories[0].ID != "plat.trnk.trnk679" {
+		t.Fatalf("Want [plat.trnk.trnk679] but got %v\n", groups[0].Repositories)
+	}
+}
+
+func TestArtifactoryAddRepositoriesToGroup(t *testing.T) {
+	var written []byte
+	posts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" && r.URL.Path == "/api/repositories" {
+			fmt.Fprintf(w, `[{"key": "plat.trnk.trnk679", "type": "LOCAL"}, {"key": "repo1", "type": "LOCAL"}, {"key": "repo2", "type": "REMOTE"}]`)
+			return
+		}
+		if r.Method == "GET" {
+			if written != nil {
+				fmt.Fprintf(w, "%s", written)
+				return
+			}
+			fmt.Fprintf(w, "%s", virtualRepo)
+			return
+		}
+		if r.Method != "POST" {
+			t.Fatalf("Wanted GET or POST but got %s\n", r.Method)
+		}
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+		written = data
+		posts++
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	client := NewArtifactoryClient(server.URL, "user", "password")
+	outcomes, rc, err := client.AddRepositoriesToGroup([]RepositoryID{"plat.trnk.trnk679", "repo1", "repo2"}, "libs-snapshot")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 200 {
+		t.Fatalf("Want 200 but got %d\n", rc)
+	}
+	if posts != 1 {
+		t.Fatalf("Want 1 POST but got %d\n", posts)
+	}
+	var repo artifactoryVirtualRepo
+	if err := json.Unmarshal(written, &repo); err != nil {
+		t.Fatalf("Not expecting an error but got one: %v\n", err)
+	}
+	if got := fmt.Sprint(repo.Repositories); got != "[plat.trnk.trnk679 repo1 repo2]" {
+		t.Fatalf("Want [plat.trnk.trnk679 repo1 repo2] but got %s\n", got)
+	}
+	if outcomes["plat.trnk.trnk679"] != MemberAlreadyPresent || outcomes["repo2"] != MemberAdded {
+		t.Fatalf("Want already present and added but got %v\n", outcomes)
+	}
+}
@@ -0,0 +1,155 @@
+package maventools
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type (
+	// Authenticator adds credentials to each HTTP request a client makes, including each retry.  Set
+	// ClientConfig.Authenticator to install one; a nil Authenticator sends ClientConfig.Username and Password with HTTP
+	// basic authentication.
+	Authenticator interface {
+		Authenticate(ctx context.Context, req *http.Request) error
+	}
+
+	// Credentials are returned by the callback of a CredentialProvider.  A Token is sent as a bearer token; otherwise
+	// Username and Password are sent with HTTP basic authentication.
+	Credentials struct {
+		Username string
+		Password string
+		Token    string
+		// When the credentials stop being valid.  The zero value means they stay valid until the server rejects them.
+		Expiry time.Time
+	}
+
+	// refresher is implemented by Authenticators whose credentials can be renewed after the server rejects them.
+	refresher interface {
+		invalidate()
+	}
+
+	basicAuth struct {
+		username, password string
+	}
+
+	bearerToken struct {
+		token string
+	}
+
+	apiKey struct {
+		header, key string
+	}
+
+	credentialProvider struct {
+		fetch  func(context.Context) (Credentials, error)
+		mu     sync.Mutex
+		cached *Credentials
+	}
+)
+
+// BasicAuth authenticates with a username and password.
+func BasicAuth(username, password string) Authenticator {
+	return basicAuth{username: username, password: password}
+}
+
+// NexusUserToken authenticates with the name and pass codes of a Nexus user token, which Nexus accepts in place of a
+// username and password.
+func NexusUserToken(nameCode, passCode string) Authenticator {
+	return basicAuth{username: nameCode, password: passCode}
+}
+
+// BearerToken authenticates with a bearer token, such as an Artifactory access token.
+func BearerToken(token string) Authenticator {
+	return bearerToken{token: token}
+}
+
+// APIKey authenticates by sending key in the given header, for example X-JFrog-Art-Api for an Artifactory API key.
+func APIKey(header, key string) Authenticator {
+	return apiKey{header: header, key: key}
+}
+
+// CredentialProvider authenticates with the credentials returned by fetch.  The credentials are reused until they expire or
+// the server rejects them with a 401, at which point fetch is called again and the rejected request repeated once.
+func CredentialProvider(fetch func(ctx context.Context) (Credentials, error)) Authenticator {
+	return &credentialProvider{fetch: fetch}
+}
+
+func (a basicAuth) Authenticate(ctx context.Context, req *http.Request) error {
+	req.SetBasicAuth(a.username, a.password)
+	return nil
+}
+
+func (a bearerToken) Authenticate(ctx context.Context, req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	return nil
+}
+
+func (a apiKey) Authenticate(ctx context.Context, req *http.Request) error {
+	req.Header.Set(a.header, a.key)
+	return nil
+}
+
+func (p *credentialProvider) Authenticate(ctx context.Context, req *http.Request) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cached == nil || (!p.cached.Expiry.IsZero() && !time.Now().Before(p.cached.Expiry)) {
+		credentials, err := p.fetch(ctx)
+		if err != nil {
+			return fmt.Errorf("CredentialProvider: %v", err)
+		}
+		p.cached = &credentials
+	}
+	if p.cached.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.cached.Token)
+	} else {
+		req.SetBasicAuth(p.cached.Username, p.cached.Password)
+	}
+	return nil
+}
+
+func (p *credentialProvider) invalidate() {
+	p.mu.Lock()
+	p.cached = nil
+	p.mu.Unlock()
+}
+
+// authenticate adds the client's credentials to req.
+func (config ClientConfig) authenticate(ctx context.Context, req *http.Request) error {
+	if config.Authenticator == nil {
+		req.SetBasicAuth(config.Username, config.Password)
+		return nil
+	}
+	return config.Authenticator.Authenticate(ctx, req)
+}
+
+// NewClientCertificateHTTPClient returns an http.Client for ClientConfig.HttpClient that presents the certificate and key in
+// the given PEM files to servers that require TLS client authentication.  If caFile is not empty, the server's certificate
+// must be signed by a certificate in it rather than by one the system trusts.
+func NewClientCertificateHTTPClient(certFile, keyFile, caFile string) (*http.Client, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}}
+	if caFile != "" {
+		data, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("NewClientCertificateHTTPClient(): no certificates found in %s\n", caFile)
+		}
+		tlsConfig.RootCAs = roots
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: transport}, nil
+}
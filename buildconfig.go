@@ -0,0 +1,199 @@
+package maventools
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type (
+	// BuildConfig describes how builds resolve dependencies from a repository group and deploy to a hosted repository, and
+	// generates the Maven and Gradle configuration that says so.  Start from NewBuildConfig and adjust from there.
+	BuildConfig struct {
+		// The group builds resolve from, through its ContentResourceURI.
+		Group RepositoryGroup
+		// The repository builds deploy to, through its ContentResourceURI.  Its Policy determines whether it receives
+		// releases, snapshots or both.
+		Hosted Repository
+		// The id under which Maven finds the credentials for the group and the hosted repository.  Defaults to the
+		// group's ID.
+		ServerID string
+		// Credentials written into the generated configuration.  References of the form ${env.X} are resolved from the
+		// environment of the build rather than written out.
+		Username string
+		Password string
+	}
+
+	// The settings.xml document written by MavenSettings.
+	settingsDocument struct {
+		XMLName xml.Name `xml:"http://maven.apache.org/SETTINGS/1.0.0 settings"`
+		Settings
+	}
+
+	// The distributionManagement section of a pom.
+	distributionManagement struct {
+		XMLName            xml.Name              `xml:"distributionManagement"`
+		Repository         *deploymentRepository `xml:"repository,omitempty"`
+		SnapshotRepository *deploymentRepository `xml:"snapshotRepository,omitempty"`
+	}
+
+	deploymentRepository struct {
+		ID   string `xml:"id"`
+		Name string `xml:"name,omitempty"`
+		URL  string `xml:"url"`
+	}
+)
+
+// NewBuildConfig returns the configuration for resolving from group and deploying to hosted, with credentials read from the
+// NEXUS_USERNAME and NEXUS_PASSWORD environment variables of the build.
+func NewBuildConfig(group RepositoryGroup, hosted Repository) BuildConfig {
+	return BuildConfig{
+		Group:    group,
+		Hosted:   hosted,
+		ServerID: string(group.ID),
+		Username: "${env.NEXUS_USERNAME}",
+		Password: "${env.NEXUS_PASSWORD}",
+	}
+}
+
+func (config BuildConfig) validate() (BuildConfig, error) {
+	if config.Group.ContentResourceURI == "" {
+		return config, fmt.Errorf("BuildConfig: repository group %s has no ContentResourceURI", config.Group.ID)
+	}
+	if config.Hosted.ContentResourceURI == "" {
+		return config, fmt.Errorf("BuildConfig: repository %s has no ContentResourceURI", config.Hosted.ID)
+	}
+	if config.ServerID == "" {
+		config.ServerID = string(config.Group.ID)
+	}
+	return config, nil
+}
+
+// MavenSettings returns a settings.xml that mirrors every repository through the group, enables snapshots for builds that
+// resolve through it, and holds the credentials for the group and the hosted repository.
+func (config BuildConfig) MavenSettings() ([]byte, error) {
+	config, err := config.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	// The mirror serves every repository, so the profile's repository URL is never used; it only turns on snapshots.
+	enabled := &SettingsRepositoryPolicy{Enabled: true}
+	central := SettingsRepository{ID: "central", URL: "http://central", Releases: enabled, Snapshots: enabled}
+	settings := settingsDocument{Settings: Settings{
+		Servers: []SettingsServer{{ID: config.ServerID, Username: config.Username, Password: config.Password}},
+		Mirrors: []SettingsMirror{{ID: config.ServerID, Name: config.Group.Name, URL: config.Group.ContentResourceURI, MirrorOf: "*"}},
+		Profiles: []SettingsProfile{{
+			ID:                 config.ServerID,
+			Repositories:       []SettingsRepository{central},
+			PluginRepositories: []SettingsRepository{central},
+		}},
+		ActiveProfiles: []string{config.ServerID},
+	}}
+	return marshalDocument(settings)
+}
+
+// DistributionManagement returns a pom distributionManagement section that deploys to the hosted repository: releases,
+// snapshots or both, as its Policy allows.
+func (config BuildConfig) DistributionManagement() ([]byte, error) {
+	config, err := config.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &deploymentRepository{ID: config.ServerID, Name: config.Hosted.Name, URL: config.Hosted.ContentResourceURI}
+	var dm distributionManagement
+	if config.Hosted.Policy != SnapshotPolicy {
+		dm.Repository = repo
+	}
+	if config.Hosted.Policy != ReleasePolicy {
+		dm.SnapshotRepository = repo
+	}
+	data, err := xml.MarshalIndent(dm, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// GradleInitScript returns a Gradle init script that makes every project, and its build script, resolve from the group
+// alone, and that adds the hosted repository as a publishing target to projects using the maven-publish plugin.
+func (config BuildConfig) GradleInitScript() ([]byte, error) {
+	config, err := config.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Resolves from repository group %s and publishes to repository %s.\n", config.Group.ID, config.Hosted.ID)
+	fmt.Fprintf(&b, "def repoUsername = %s\n", groovyValue(config.Username))
+	fmt.Fprintf(&b, "def repoPassword = %s\n", groovyValue(config.Password))
+	b.WriteString("\nallprojects {\n")
+	b.WriteString("    buildscript {\n        repositories {\n            clear()\n")
+	writeGradleRepository(&b, "            ", config.ServerID, config.Group.ContentResourceURI)
+	b.WriteString("        }\n    }\n")
+	b.WriteString("    repositories {\n        clear()\n")
+	writeGradleRepository(&b, "        ", config.ServerID, config.Group.ContentResourceURI)
+	b.WriteString("    }\n")
+	b.WriteString("    plugins.withId('maven-publish') {\n        publishing {\n            repositories {\n")
+	writeGradleRepository(&b, "                ", string(config.Hosted.ID), config.Hosted.ContentResourceURI)
+	b.WriteString("            }\n        }\n    }\n}\n")
+	return []byte(b.String()), nil
+}
+
+func writeGradleRepository(b *strings.Builder, indent, name, url string) {
+	fmt.Fprintf(b, "%smaven {\n", indent)
+	fmt.Fprintf(b, "%s    name = %s\n", indent, groovyString(gradleName(name)))
+	fmt.Fprintf(b, "%s    url = %s\n", indent, groovyString(url))
+	fmt.Fprintf(b, "%s    credentials {\n", indent)
+	fmt.Fprintf(b, "%s        username = repoUsername\n", indent)
+	fmt.Fprintf(b, "%s        password = repoPassword\n", indent)
+	fmt.Fprintf(b, "%s    }\n", indent)
+	fmt.Fprintf(b, "%s}\n", indent)
+}
+
+// gradleName turns a repository ID into a Gradle repository name, which may only contain letters and digits since Gradle
+// derives task names such as publishToSnapshotsRepository from it.  An ID with neither becomes mavenRepo.
+func gradleName(id string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9':
+			if upper {
+				r = unicode.ToUpper(r)
+			}
+			b.WriteRune(r)
+			upper = false
+		default:
+			upper = b.Len() > 0
+		}
+	}
+	if b.Len() == 0 {
+		return "mavenRepo"
+	}
+	return b.String()
+}
+
+// groovyValue renders a credential as a Groovy expression: a ${env.X} reference reads the environment variable X, anything
+// else is a string literal.
+func groovyValue(value string) string {
+	if m := envReference.FindStringSubmatch(value); m != nil && m[0] == value {
+		return fmt.Sprintf("System.getenv(%s)", groovyString(m[1]))
+	}
+	return groovyString(value)
+}
+
+// groovyString quotes s as a single-quoted Groovy string, which does not interpolate.
+func groovyString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`).Replace(s) + "'"
+}
+
+func marshalDocument(v interface{}) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte(xml.Header), data...), '\n'), nil
+}
@@ -0,0 +1,131 @@
+package maventools
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testBuildConfig() BuildConfig {
+	group := RepositoryGroup{ID: "snapshotgroup", Name: "Snapshot Group", ContentResourceURI: "http://localhost:8081/nexus/content/groups/snapshotgroup"}
+	hosted := Repository{ID: "feature-x", Name: "feature-x", Policy: SnapshotPolicy, ContentResourceURI: "http://localhost:8081/nexus/content/repositories/feature-x"}
+	return NewBuildConfig(group, hosted)
+}
+
+func TestMavenSettings(t *testing.T) {
+	data, err := testBuildConfig().MavenSettings()
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if !strings.HasPrefix(string(data), `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+`<settings xmlns="http://maven.apache.org/SETTINGS/1.0.0">`) {
+		t.Fatalf("Want a settings document but got %s\n", data)
+	}
+
+	// The generated settings load back, with the environment supplying the credentials.
+	t.Setenv("NEXUS_USERNAME", "deployer")
+	t.Setenv("NEXUS_PASSWORD", "s3cr3t")
+	dir := t.TempDir()
+	path := writeFile(t, dir, "settings.xml", string(data))
+	settings, err := LoadSettings(path, filepath.Join(dir, "settings-security.xml"))
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	client, err := settings.NexusClientForServer("snapshotgroup")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if client.BaseURL != "http://localhost:8081/nexus" || client.Username != "deployer" || client.Password != "s3cr3t" {
+		t.Fatalf("Want deployer@http://localhost:8081/nexus but got %s@%s\n", client.Username, client.BaseURL)
+	}
+	if len(settings.Mirrors) != 1 || settings.Mirrors[0].MirrorOf != "*" {
+		t.Fatalf("Want a mirror of * but got %+v\n", settings.Mirrors)
+	}
+	if len(settings.ActiveProfiles) != 1 || settings.ActiveProfiles[0] != "snapshotgroup" {
+		t.Fatalf("Want active profile snapshotgroup but got %v\n", settings.ActiveProfiles)
+	}
+	if repos := settings.Profiles[0].Repositories; len(repos) != 1 || repos[0].Snapshots == nil || !repos[0].Snapshots.Enabled {
+		t.Fatalf("Want snapshots enabled but got %+v\n", repos)
+	}
+}
+
+func TestDistributionManagement(t *testing.T) {
+	config := testBuildConfig()
+	data, err := config.DistributionManagement()
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	want := `<distributionManagement>
+  <snapshotRepository>
+    <id>snapshotgroup</id>
+    <name>feature-x</name>
+    <url>http://localhost:8081/nexus/content/repositories/feature-x</url>
+  </snapshotRepository>
+</distributionManagement>
+`
+	if string(data) != want {
+		t.Fatalf("Want %s but got %s\n", want, data)
+	}
+
+	config.Hosted.Policy = ReleasePolicy
+	data, _ = config.DistributionManagement()
+	if !strings.Contains(string(data), "<repository>") || strings.Contains(string(data), "<snapshotRepository>") {
+		t.Fatalf("Want a release repository only but got %s\n", data)
+	}
+	config.Hosted.Policy = MixedPolicy
+	data, _ = config.DistributionManagement()
+	if !strings.Contains(string(data), "<repository>") || !strings.Contains(string(data), "<snapshotRepository>") {
+		t.Fatalf("Want both repositories but got %s\n", data)
+	}
+}
+
+func TestGradleInitScript(t *testing.T) {
+	config := testBuildConfig()
+	config.Password = "it's"
+	data, err := config.GradleInitScript()
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	script := string(data)
+	for _, want := range []string{
+		`def repoUsername = System.getenv('NEXUS_USERNAME')`,
+		`def repoPassword = 'it\'s'`,
+		`url = 'http://localhost:8081/nexus/content/groups/snapshotgroup'`,
+		`plugins.withId('maven-publish')`,
+		`name = 'featureX'`,
+		`url = 'http://localhost:8081/nexus/content/repositories/feature-x'`,
+	} {
+		if !strings.Contains(script, want) {
+			t.Fatalf("Want %s in\n%s\n", want, script)
+		}
+	}
+	if strings.Count(script, "{") != strings.Count(script, "}") {
+		t.Fatalf("Want balanced braces but got\n%s\n", script)
+	}
+}
+
+func TestGradleName(t *testing.T) {
+	for id, want := range map[string]string{
+		"feature-x":       "featureX",
+		"plat.trnk.trnk6": "platTrnkTrnk6",
+		"-snapshots":      "snapshots",
+		"---":             "mavenRepo",
+		"快照":              "mavenRepo",
+	} {
+		if got := gradleName(id); got != want {
+			t.Fatalf("Want %s for %q but got %s\n", want, id, got)
+		}
+	}
+}
+
+func TestBuildConfigRequiresContentURIs(t *testing.T) {
+	config := testBuildConfig()
+	config.Hosted.ContentResourceURI = ""
+	if _, err := config.MavenSettings(); err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+	config = testBuildConfig()
+	config.Group.ContentResourceURI = ""
+	if _, err := config.GradleInitScript(); err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+}
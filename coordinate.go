@@ -0,0 +1,142 @@
+package maventools
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Coordinate identifies a Maven artifact: a file in a repository laid out the Maven 2 way.
+type Coordinate struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+	// Distinguishes the files of one version built from the same pom, for example sources or javadoc.  Usually empty.
+	Classifier string
+	// The file extension, or packaging.  Defaults to jar.
+	Extension string
+}
+
+var (
+	// Group and artifact IDs are restricted as Maven restricts them.  Each dot-separated segment of a group ID becomes a
+	// directory, so none may be empty.
+	coordinateID      = regexp.MustCompile(`^[A-Za-z0-9_.\-]+$`)
+	coordinateGroupID = regexp.MustCompile(`^[A-Za-z0-9_\-]+(\.[A-Za-z0-9_\-]+)*$`)
+	// Versions, classifiers and extensions may not contain separators of a coordinate or of a path.
+	coordinatePart = regexp.MustCompile(`^[^/\\:\s]+$`)
+	// A snapshot version as deployed, for example 1.0-20240102.030405-6 for 1.0-SNAPSHOT.
+	timestampedVersion = regexp.MustCompile(`^(.*)-([0-9]{8}\.[0-9]{6})-([0-9]+)$`)
+)
+
+const snapshotSuffix = "-SNAPSHOT"
+
+// ParseCoordinate parses a coordinate of the form groupId:artifactId[:extension[:classifier]]:version, as used by Maven's
+// dependency and deploy plugins.  The extension defaults to jar.
+func ParseCoordinate(s string) (Coordinate, error) {
+	parts := strings.Split(s, ":")
+	var c Coordinate
+	switch len(parts) {
+	case 3:
+		c = Coordinate{GroupID: parts[0], ArtifactID: parts[1], Version: parts[2]}
+	case 4:
+		c = Coordinate{GroupID: parts[0], ArtifactID: parts[1], Extension: parts[2], Version: parts[3]}
+	case 5:
+		c = Coordinate{GroupID: parts[0], ArtifactID: parts[1], Extension: parts[2], Classifier: parts[3], Version: parts[4]}
+	default:
+		return Coordinate{}, fmt.Errorf("ParseCoordinate(): %q is not of the form groupId:artifactId[:extension[:classifier]]:version", s)
+	}
+	if c.Extension == "" {
+		c.Extension = "jar"
+	}
+	if err := c.Validate(); err != nil {
+		return Coordinate{}, err
+	}
+	return c, nil
+}
+
+// Validate reports whether the coordinate can name a file in a repository.  An empty Extension counts as jar.
+func (c Coordinate) Validate() error {
+	if !coordinateGroupID.MatchString(c.GroupID) {
+		return fmt.Errorf("Coordinate: invalid groupId %q", c.GroupID)
+	}
+	if !coordinateID.MatchString(c.ArtifactID) || strings.Trim(c.ArtifactID, ".") == "" {
+		return fmt.Errorf("Coordinate: invalid artifactId %q", c.ArtifactID)
+	}
+	if !coordinatePart.MatchString(c.Version) || c.Version == "." || c.Version == ".." {
+		return fmt.Errorf("Coordinate: invalid version %q", c.Version)
+	}
+	if c.Classifier != "" && !coordinatePart.MatchString(c.Classifier) {
+		return fmt.Errorf("Coordinate: invalid classifier %q", c.Classifier)
+	}
+	if c.Extension != "" && !coordinatePart.MatchString(c.Extension) {
+		return fmt.Errorf("Coordinate: invalid extension %q", c.Extension)
+	}
+	return nil
+}
+
+// String formats the coordinate as ParseCoordinate parses it, leaving out a jar extension when there is no classifier.
+func (c Coordinate) String() string {
+	ext := c.extension()
+	switch {
+	case c.Classifier != "":
+		return strings.Join([]string{c.GroupID, c.ArtifactID, ext, c.Classifier, c.Version}, ":")
+	case ext != "jar":
+		return strings.Join([]string{c.GroupID, c.ArtifactID, ext, c.Version}, ":")
+	default:
+		return strings.Join([]string{c.GroupID, c.ArtifactID, c.Version}, ":")
+	}
+}
+
+func (c Coordinate) extension() string {
+	if c.Extension == "" {
+		return "jar"
+	}
+	return c.Extension
+}
+
+// IsSnapshot reports whether the coordinate names a snapshot, either as 1.0-SNAPSHOT or as a timestamped version such as
+// 1.0-20240102.030405-6.
+func (c Coordinate) IsSnapshot() bool {
+	return strings.HasSuffix(c.Version, snapshotSuffix) || timestampedVersion.MatchString(c.Version)
+}
+
+// BaseVersion returns the version that names the directory of the coordinate's files: the version itself, or
+// 1.0-SNAPSHOT for a timestamped snapshot version such as 1.0-20240102.030405-6.
+func (c Coordinate) BaseVersion() string {
+	if m := timestampedVersion.FindStringSubmatch(c.Version); m != nil {
+		return m[1] + snapshotSuffix
+	}
+	return c.Version
+}
+
+// ArtifactPath returns the directory of all versions of the artifact, for example org/example/lib.  The artifact's
+// maven-metadata.xml lives there.
+func (c Coordinate) ArtifactPath() string {
+	return strings.Replace(c.GroupID, ".", "/", -1) + "/" + c.ArtifactID
+}
+
+// VersionPath returns the directory of the files of the coordinate's version, for example org/example/lib/1.0.
+func (c Coordinate) VersionPath() string {
+	return c.ArtifactPath() + "/" + c.BaseVersion()
+}
+
+// FileName returns the name of the coordinate's file, for example lib-1.0-sources.jar.
+func (c Coordinate) FileName() string {
+	name := c.ArtifactID + "-" + c.Version
+	if c.Classifier != "" {
+		name += "-" + c.Classifier
+	}
+	return name + "." + c.extension()
+}
+
+// Path returns the path of the coordinate's file relative to the root of a Maven 2 repository, for example
+// org/example/lib/1.0/lib-1.0-sources.jar.
+func (c Coordinate) Path() string {
+	return c.VersionPath() + "/" + c.FileName()
+}
+
+// URL returns the URL of the coordinate's file in the repository whose content is at contentResourceURI, such as the
+// ContentResourceURI of a Repository or RepositoryGroup.
+func (c Coordinate) URL(contentResourceURI string) string {
+	return strings.TrimSuffix(contentResourceURI, "/") + "/" + c.Path()
+}
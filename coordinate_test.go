@@ -0,0 +1,97 @@
+package maventools
+
+import (
+	"testing"
+)
+
+func TestParseCoordinate(t *testing.T) {
+	var tests = []struct {
+		s    string
+		want Coordinate
+		path string
+	}{
+		{"org.example:lib:1.0", Coordinate{"org.example", "lib", "1.0", "", "jar"}, "org/example/lib/1.0/lib-1.0.jar"},
+		{"org.example:lib:pom:1.0", Coordinate{"org.example", "lib", "1.0", "", "pom"}, "org/example/lib/1.0/lib-1.0.pom"},
+		{"org.example:lib:jar:sources:1.0", Coordinate{"org.example", "lib", "1.0", "sources", "jar"}, "org/example/lib/1.0/lib-1.0-sources.jar"},
+		{"com.xoom:my-lib:war:2.1-SNAPSHOT", Coordinate{"com.xoom", "my-lib", "2.1-SNAPSHOT", "", "war"}, "com/xoom/my-lib/2.1-SNAPSHOT/my-lib-2.1-SNAPSHOT.war"},
+		{"com.xoom:my-lib:2.1-20240102.030405-6", Coordinate{"com.xoom", "my-lib", "2.1-20240102.030405-6", "", "jar"}, "com/xoom/my-lib/2.1-SNAPSHOT/my-lib-2.1-20240102.030405-6.jar"},
+	}
+	for _, test := range tests {
+		c, err := ParseCoordinate(test.s)
+		if err != nil {
+			t.Fatalf("Expecting no error but got one: %v\n", err)
+		}
+		if c != test.want {
+			t.Fatalf("Want %+v but got %+v\n", test.want, c)
+		}
+		if c.Path() != test.path {
+			t.Fatalf("Want %s but got %s\n", test.path, c.Path())
+		}
+		if c.String() != test.s {
+			t.Fatalf("Want %s but got %s\n", test.s, c.String())
+		}
+	}
+}
+
+func TestParseCoordinateErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"org.example:lib",
+		"org.example:lib:jar:sources:extra:1.0",
+		"org.example::1.0",
+		"org/example:lib:1.0",
+		"org.example:lib:",
+		"org.example:lib:../1.0",
+		"org.example:lib:..",
+		"org.example:lib:jar:has space:1.0",
+		"org.example:..:1.0",
+		"org.example:.:1.0",
+		"..:lib:1.0",
+		".:lib:1.0",
+		"org..example:lib:1.0",
+		".org:lib:1.0",
+		"org.:lib:1.0",
+	} {
+		if _, err := ParseCoordinate(s); err == nil {
+			t.Fatalf("Expecting an error for %q but did not get one\n", s)
+		}
+	}
+}
+
+func TestCoordinateSnapshot(t *testing.T) {
+	var tests = []struct {
+		version  string
+		snapshot bool
+		base     string
+	}{
+		{"1.0", false, "1.0"},
+		{"1.0-SNAPSHOT", true, "1.0-SNAPSHOT"},
+		{"1.0-20240102.030405-6", true, "1.0-SNAPSHOT"},
+		{"1.0-rc-1", false, "1.0-rc-1"},
+	}
+	for _, test := range tests {
+		c := Coordinate{GroupID: "org.example", ArtifactID: "lib", Version: test.version}
+		if c.IsSnapshot() != test.snapshot {
+			t.Fatalf("Want %v for %s but got %v\n", test.snapshot, test.version, c.IsSnapshot())
+		}
+		if c.BaseVersion() != test.base {
+			t.Fatalf("Want %s but got %s\n", test.base, c.BaseVersion())
+		}
+	}
+}
+
+func TestCoordinateURL(t *testing.T) {
+	c := Coordinate{GroupID: "org.example", ArtifactID: "lib", Version: "1.0", Classifier: "javadoc"}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	want := "http://localhost:8081/nexus/content/groups/public/org/example/lib/1.0/lib-1.0-javadoc.jar"
+	for _, base := range []string{"http://localhost:8081/nexus/content/groups/public", "http://localhost:8081/nexus/content/groups/public/"} {
+		if got := c.URL(base); got != want {
+			t.Fatalf("Want %s but got %s\n", want, got)
+		}
+	}
+	if c.ArtifactPath() != "org/example/lib" || c.VersionPath() != "org/example/lib/1.0" {
+		t.Fatalf("Want org/example/lib and org/example/lib/1.0 but got %s and %s\n", c.ArtifactPath(), c.VersionPath())
+	}
+}
@@ -0,0 +1,107 @@
+package maventools
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCreateGroup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("Wanted POST but got %s\n", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/service/local/repo_groups") {
+			t.Fatalf("Wanted URL suffix /service/local/repo_groups but got: %s\n", r.URL.Path)
+		}
+		if r.Header.Get("Accept") != "application/json" {
+			t.Fatalf("Wanted application/json but got %s for Accept header", r.Header.Get("Accept"))
+		}
+		if r.Header.Get("Content-type") != "application/json" {
+			t.Fatalf("Wanted application/json but got %s for Content-type header", r.Header.Get("Content-type"))
+		}
+		authHeader := r.Header.Get("Authorization")
+		if authHeader == "" {
+			t.Fatalf("Wanted an Authorization header but found none")
+		}
+		base64 := authHeader[len("Basic "):]
+		if base64 != "dXNlcjpwYXNzd29yZA==" {
+			t.Fatalf("Wanted dXNlcjpwYXNzd29yZA== but got %s\n", base64)
+		}
+
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+
+		var repogroup repoGroup
+		if err := json.Unmarshal(data, &repogroup); err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+		if repogroup.Data.ID != "teamgroup" {
+			t.Fatalf("Want teamgroup but got %v\n", repogroup.Data.ID)
+		}
+		if repogroup.Data.Name != "Team Group" {
+			t.Fatalf("Want Team Group but got %v\n", repogroup.Data.Name)
+		}
+		if repogroup.Data.Provider != "maven2" {
+			t.Fatalf("Want maven2 but got %v\n", repogroup.Data.Provider)
+		}
+		if repogroup.Data.RepoType != "group" {
+			t.Fatalf("Want group but got %v\n", repogroup.Data.RepoType)
+		}
+		if !repogroup.Data.Exposed {
+			t.Fatalf("Want true but got false\n")
+		}
+		if !strings.HasSuffix(repogroup.Data.ContentResourceURI, "/content/groups/teamgroup") {
+			t.Fatalf("Want suffix /content/groups/teamgroup but got %v\n", repogroup.Data.ContentResourceURI)
+		}
+		if len(repogroup.Data.Repositories) != 2 {
+			t.Fatalf("Want 2 but got %d\n", len(repogroup.Data.Repositories))
+		}
+		if repogroup.Data.Repositories[0].ID != "releases" || repogroup.Data.Repositories[1].ID != "snapshots" {
+			t.Fatalf("Want releases then snapshots but got %v\n", repogroup.Data.Repositories)
+		}
+
+		w.WriteHeader(201)
+	}))
+	defer server.Close()
+
+	options := NewRepositoryGroupOptions("teamgroup", "releases", "snapshots")
+	options.Name = "Team Group"
+
+	client := NewNexusClient(server.URL, "user", "password")
+	rc, err := client.CreateRepositoryGroup(options)
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 201 {
+		t.Fatalf("Want 201 but got %d\n", rc)
+	}
+}
+
+func TestCreateGroupWithError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(400)
+	}))
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	rc, err := client.CreateRepositoryGroup(NewRepositoryGroupOptions("teamgroup"))
+	if err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+	if rc != 400 {
+		t.Fatalf("Want 400 but got %d\n", rc)
+	}
+}
+
+func TestCreateGroupWithoutID(t *testing.T) {
+	client := NewNexusClient("http://localhost:0", "user", "password")
+	if _, err := client.CreateRepositoryGroup(RepositoryGroupOptions{}); err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+}
@@ -11,6 +11,7 @@ import (
 )
 
 func TestDeleteFromGroup(t *testing.T) {
+	var written []byte
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !(r.Method == "GET" || r.Method == "PUT") {
 			t.Fatalf("Wanted GET or PUT but got %s\n", r.Method)
@@ -36,6 +37,9 @@ func TestDeleteFromGroup(t *testing.T) {
 }`
 
 		if r.Method == "GET" {
+			if written != nil {
+				group = string(written)
+			}
 			fmt.Fprintf(w, "%s", group)
 			return
 		}
@@ -62,6 +66,7 @@ func TestDeleteFromGroup(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Not expecting an error but got one: %v\n", err)
 		}
+		written = data
 
 		var repogroup repoGroup
 		if err := json.Unmarshal(data, &repogroup); err != nil {
@@ -70,7 +75,7 @@ func TestDeleteFromGroup(t *testing.T) {
 		if len(repogroup.Data.Repositories) != 0 {
 			t.Fatalf("Want 0 but got %d\n", len(repogroup.Data.Repositories))
 		}
-		if repoIsInGroup("plat.trnk.trnk679", repogroup) {
+		if isMember("plat.trnk.trnk679", repogroup) {
 			t.Fatalf("Expecting false but got true\n")
 		}
 		w.WriteHeader(200)
@@ -0,0 +1,46 @@
+package maventools
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDeleteGroup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Fatalf("Wanted DELETE but got %s\n", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/service/local/repo_groups/teamgroup") {
+			t.Fatalf("Wanted URL suffix /service/local/repo_groups/teamgroup but got: %s\n", r.URL.Path)
+		}
+		w.WriteHeader(204)
+	}))
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	rc, err := client.DeleteRepositoryGroup("teamgroup")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 204 {
+		t.Fatalf("Want 204 but got %d\n", rc)
+	}
+}
+
+func TestDeleteGroupNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+	}))
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	rc, err := client.DeleteRepositoryGroup("teamgroup")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 404 {
+		t.Fatalf("Want 404 but got %d\n", rc)
+	}
+}
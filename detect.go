@@ -0,0 +1,111 @@
+package maventools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type (
+	// Product identifies the kind of repository manager found at a BaseURL.
+	Product string
+
+	// ServerInfo describes the repository manager detected by NewClient.
+	ServerInfo struct {
+		Product Product
+		// Version as reported by the server, or empty if the server does not disclose it.
+		Version string
+	}
+
+	// The payload of the Nexus 2 status resource.
+	nexus2Status struct {
+		Data struct {
+			AppName string `json:"appName"`
+			Version string `json:"version"`
+		} `json:"data"`
+	}
+
+	// The payload of the Artifactory system version resource.
+	artifactoryVersion struct {
+		Version  string `json:"version"`
+		Revision string `json:"revision"`
+	}
+)
+
+const (
+	Nexus2      Product = "nexus2"
+	Nexus3      Product = "nexus3"
+	Artifactory Product = "artifactory"
+)
+
+// NewClient probes the server at config.BaseURL and returns the IClient implementation that speaks its API, along with the
+// detected product and version.  The Nexus 2 status resource, the Nexus 3 status endpoint and the Artifactory system version
+// resource are tried in that order.  If config.HttpClient is nil, a default http.Client is used.  The probes are made like
+// any other request of the client, so config's RetryPolicy, Authenticator, Logger, Observer and TracerProvider apply to them.
+//
+// NewClient uses context.Background internally; to specify the context, use NewClientContext.
+func NewClient(config ClientConfig) (IClient, ServerInfo, error) {
+	return NewClientContext(context.Background(), config)
+}
+
+// NewClientContext is like NewClient but uses ctx for its HTTP requests and any pending retries.
+func NewClientContext(ctx context.Context, config ClientConfig) (_ IClient, _ ServerInfo, err error) {
+	if config.HttpClient == nil {
+		config.HttpClient = &http.Client{}
+	}
+	ctx, span := config.startOperation(ctx, "NewClient")
+	defer func() { span.end(err) }()
+
+	if rc, _, data, err := config.probe(ctx, "/service/local/status"); err != nil {
+		return nil, ServerInfo{}, err
+	} else if rc == 200 {
+		var status nexus2Status
+		if err := json.Unmarshal(data, &status); err == nil && status.Data.Version != "" {
+			return NexusClient{ClientConfig: config, groups: newGroupBatcher()}, ServerInfo{Product: Nexus2, Version: status.Data.Version}, nil
+		}
+	}
+
+	if rc, header, _, err := config.probe(ctx, "/service/rest/v1/status"); err != nil {
+		return nil, ServerInfo{}, err
+	} else if rc == 200 {
+		return Nexus3Client{config}, ServerInfo{Product: Nexus3, Version: nexus3Version(header.Get("Server"))}, nil
+	}
+
+	if rc, _, data, err := config.probe(ctx, "/api/system/version"); err != nil {
+		return nil, ServerInfo{}, err
+	} else if rc == 200 {
+		var version artifactoryVersion
+		if err := json.Unmarshal(data, &version); err == nil && version.Version != "" {
+			return ArtifactoryClient{config}, ServerInfo{Product: Artifactory, Version: version.Version}, nil
+		}
+	}
+
+	return nil, ServerInfo{}, fmt.Errorf("NewClient(): unable to detect a Nexus 2, Nexus 3 or Artifactory server at %s\n", config.BaseURL)
+}
+
+// probe issues a GET for the given path relative to BaseURL and returns the response code, headers and body.  A response
+// other than 200 only means that the server is not the product probed for, so it is returned without an error.
+func (config ClientConfig) probe(ctx context.Context, path string) (int, http.Header, []byte, error) {
+	var header http.Header
+	rc, data, err := config.send(ctx, request{op: "NewClient", method: "GET", path: path, header: &header})
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return rc, header, data, nil
+	}
+	return rc, header, data, err
+}
+
+// nexus3Version extracts the version from a Nexus 3 Server header of the form "Nexus/3.37.3-02 (OSS)".
+func nexus3Version(server string) string {
+	if !strings.HasPrefix(server, "Nexus/") {
+		return ""
+	}
+	version := strings.TrimPrefix(server, "Nexus/")
+	if i := strings.Index(version, " "); i != -1 {
+		version = version[:i]
+	}
+	return version
+}
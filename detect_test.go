@@ -0,0 +1,137 @@
+package maventools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDetectNexus2(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/service/local/status" {
+			t.Fatalf("Wanted /service/local/status but got: %s\n", r.URL.Path)
+		}
+		fmt.Fprintf(w, `{"data": {"appName": "Nexus Repository Manager", "version": "2.14.5-02"}}`)
+	}))
+	defer server.Close()
+
+	client, info, err := NewClient(ClientConfig{BaseURL: server.URL, Username: "user", Password: "password"})
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if _, ok := client.(NexusClient); !ok {
+		t.Fatalf("Want NexusClient but got %T\n", client)
+	}
+	if info.Product != Nexus2 {
+		t.Fatalf("Want nexus2 but got %v\n", info.Product)
+	}
+	if info.Version != "2.14.5-02" {
+		t.Fatalf("Want 2.14.5-02 but got %v\n", info.Version)
+	}
+}
+
+func TestDetectNexus3(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/service/rest/v1/status" {
+			w.WriteHeader(404)
+			return
+		}
+		w.Header().Set("Server", "Nexus/3.37.3-02 (OSS)")
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	client, info, err := NewClient(ClientConfig{BaseURL: server.URL, Username: "user", Password: "password"})
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if _, ok := client.(Nexus3Client); !ok {
+		t.Fatalf("Want Nexus3Client but got %T\n", client)
+	}
+	if info.Product != Nexus3 {
+		t.Fatalf("Want nexus3 but got %v\n", info.Product)
+	}
+	if info.Version != "3.37.3-02" {
+		t.Fatalf("Want 3.37.3-02 but got %v\n", info.Version)
+	}
+}
+
+func TestDetectArtifactory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/system/version" {
+			w.WriteHeader(404)
+			return
+		}
+		fmt.Fprintf(w, `{"version": "7.41.4", "revision": "74104900"}`)
+	}))
+	defer server.Close()
+
+	client, info, err := NewClient(ClientConfig{BaseURL: server.URL, Username: "user", Password: "password"})
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if _, ok := client.(ArtifactoryClient); !ok {
+		t.Fatalf("Want ArtifactoryClient but got %T\n", client)
+	}
+	if info.Product != Artifactory {
+		t.Fatalf("Want artifactory but got %v\n", info.Product)
+	}
+	if info.Version != "7.41.4" {
+		t.Fatalf("Want 7.41.4 but got %v\n", info.Version)
+	}
+}
+
+func TestDetectUnknown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+	}))
+	defer server.Close()
+
+	_, _, err := NewClient(ClientConfig{BaseURL: server.URL, Username: "user", Password: "password"})
+	if err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+}
+
+func TestDetectRetriesAndObserves(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(503)
+			return
+		}
+		fmt.Fprintf(w, `{"data": {"appName": "Nexus Repository Manager", "version": "2.14.5-02"}}`)
+	}))
+	defer server.Close()
+
+	observer := &recordingObserver{}
+	config := ClientConfig{BaseURL: server.URL, Username: "user", Password: "password", RetryPolicy: quickRetries(), Observer: observer}
+	_, info, err := NewClientContext(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if info.Product != Nexus2 || requests != 2 {
+		t.Fatalf("Want nexus2 after 2 requests but got %v after %d\n", info.Product, requests)
+	}
+	if len(observer.after) != 2 || observer.after[0].Op != "NewClient" || observer.after[1].Attempt != 2 {
+		t.Fatalf("Want 2 attempts of NewClient but got %+v\n", observer.after)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := NewClientContext(ctx, config); err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+}
+
+func TestNexus3Version(t *testing.T) {
+	if v := nexus3Version("Nexus/3.37.3-02 (OSS)"); v != "3.37.3-02" {
+		t.Fatalf("Want 3.37.3-02 but got %v\n", v)
+	}
+	if v := nexus3Version("nginx"); v != "" {
+		t.Fatalf("Want empty but got %v\n", v)
+	}
+}
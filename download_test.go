@@ -0,0 +1,91 @@
+package maventools
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDownloadArtifact(t *testing.T) {
+	server := newRecordingServer()
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	c := Coordinate{GroupID: "org.example", ArtifactID: "lib", Version: "1.0"}
+	if _, err := client.UploadArtifact("releases", c, strings.NewReader("jar content")); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+
+	var buf bytes.Buffer
+	rc, err := client.DownloadArtifact(server.URL+"/content/repositories/releases/", c, &buf)
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 200 {
+		t.Fatalf("Want 200 but got %d\n", rc)
+	}
+	if buf.String() != "jar content" {
+		t.Fatalf("Want jar content but got %s\n", buf.String())
+	}
+}
+
+func TestDownloadArtifactFromGroup(t *testing.T) {
+	server := newRecordingServer()
+	defer server.Close()
+
+	// A group serves the files of its members under its own content URI; the sha1 file is in md5sum's format.
+	path := "/content/groups/public/org/example/lib/1.0/lib-1.0.pom"
+	server.files[path+".sha1"] = []byte("2FD4E1C67A2D28FCED849EE1BB76E7391B93EB12  lib-1.0.pom\n")
+	server.files[path] = []byte("The quick brown fox jumps over the lazy dog")
+
+	client := NewNexusClient(server.URL, "user", "password")
+	group := RepositoryGroup{ID: "public", ContentResourceURI: server.URL + "/content/groups/public"}
+	c := Coordinate{GroupID: "org.example", ArtifactID: "lib", Version: "1.0", Extension: "pom"}
+	var buf bytes.Buffer
+	if _, err := client.DownloadArtifact(group.ContentResourceURI, c, &buf); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if buf.String() != "The quick brown fox jumps over the lazy dog" {
+		t.Fatalf("Want the pom but got %s\n", buf.String())
+	}
+}
+
+func TestDownloadArtifactChecksumMismatch(t *testing.T) {
+	server := newRecordingServer()
+	defer server.Close()
+
+	client := NewNexus3Client(server.URL, "user", "password")
+	c := Coordinate{GroupID: "org.example", ArtifactID: "lib", Version: "1.0"}
+	if _, err := client.UploadArtifact("maven-releases", c, strings.NewReader("jar content")); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	server.files["/repository/maven-releases/org/example/lib/1.0/lib-1.0.jar"] = []byte("tampered")
+
+	var buf bytes.Buffer
+	_, err := client.DownloadArtifact(server.URL+"/repository/maven-releases", c, &buf)
+	var checksumErr *ChecksumError
+	if !errors.As(err, &checksumErr) {
+		t.Fatalf("Want a *ChecksumError but got %v\n", err)
+	}
+	if checksumErr.Algorithm != "sha256" || checksumErr.Op != "Nexus3Client.DownloadArtifact" || checksumErr.Expected == checksumErr.Actual {
+		t.Fatalf("Want a sha256 mismatch from Nexus3Client.DownloadArtifact but got %+v\n", *checksumErr)
+	}
+}
+
+func TestDownloadArtifactRequiresChecksum(t *testing.T) {
+	server := newRecordingServer()
+	defer server.Close()
+
+	server.files["/libs-release/org/example/lib/1.0/lib-1.0.jar"] = []byte("jar content")
+
+	client := NewArtifactoryClient(server.URL, "user", "password")
+	c := Coordinate{GroupID: "org.example", ArtifactID: "lib", Version: "1.0"}
+	var buf bytes.Buffer
+	if _, err := client.DownloadArtifact(server.URL+"/libs-release", c, &buf); err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("Want nothing written but got %s\n", buf.String())
+	}
+}
@@ -0,0 +1,129 @@
+package maventools
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Kinds of failure a StatusError can represent.  Test for them with errors.Is:
+//
+//	if errors.Is(err, maventools.ErrNotFound) { ... }
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrForbidden     = errors.New("forbidden")
+	ErrConflict      = errors.New("conflict")
+	ErrServerError   = errors.New("server error")
+)
+
+// StatusError is returned when a repository manager answers a request with an unexpected HTTP status.  Use errors.As to
+// recover the details of the failed exchange.
+type StatusError struct {
+	// Op names the client operation, for example NexusClient.DeleteRepository.
+	Op         string
+	Method     string
+	URL        string
+	StatusCode int
+	// Body is the response body, which usually carries the server's explanation of the failure.
+	Body string
+	// Kind is one of the Err* values above, or nil if the status does not map to one of them.
+	Kind error
+}
+
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf("%s: %s %s: unexpected response status: %d", e.Op, e.Method, e.URL, e.StatusCode)
+	if e.Body != "" {
+		msg += " (" + e.Body + ")"
+	}
+	return msg
+}
+
+// Unwrap returns the error's Kind, so that errors.Is(err, ErrNotFound) and friends work on a StatusError.
+func (e *StatusError) Unwrap() error {
+	return e.Kind
+}
+
+// newStatusError describes an unexpected response to a request made by the client operation op.
+func newStatusError(op string, resp *http.Response, body []byte) *StatusError {
+	e := &StatusError{Op: op, StatusCode: resp.StatusCode, Body: string(body)}
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e.URL = resp.Request.URL.String()
+	}
+	e.Kind = statusKind(resp.StatusCode, e.Body)
+	return e
+}
+
+// statusKind classifies a response status.  Nexus 2, Nexus 3 and Artifactory all reject the creation of a duplicate
+// repository with a 400 and a message saying so rather than with a 409, hence the look at the body.  Some versions of
+// Artifactory likewise answer a request for a missing repository with a 400 saying that it does not exist.
+func statusKind(statusCode int, body string) error {
+	switch {
+	case (statusCode == http.StatusBadRequest || statusCode == http.StatusConflict) && mentionsDuplicate(body):
+		return ErrAlreadyExists
+	case statusCode == http.StatusBadRequest && mentionsMissing(body):
+		return ErrNotFound
+	case statusCode == http.StatusNotFound:
+		return ErrNotFound
+	case statusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case statusCode == http.StatusForbidden:
+		return ErrForbidden
+	case statusCode == http.StatusConflict:
+		return ErrConflict
+	case statusCode >= 500:
+		return ErrServerError
+	}
+	return nil
+}
+
+func mentionsDuplicate(body string) bool {
+	body = strings.ToLower(body)
+	return strings.Contains(body, "already exists") || strings.Contains(body, "already used")
+}
+
+func mentionsMissing(body string) bool {
+	body = strings.ToLower(body)
+	return strings.Contains(body, "does not exist") || strings.Contains(body, "doesn't exist") || strings.Contains(body, "not found")
+}
+
+// ConflictError is returned when a change to the members of a repository group keeps being undone by concurrent writers.  It
+// wraps ErrConflict, so errors.Is(err, ErrConflict) holds for it as well as for a 409 StatusError.
+type ConflictError struct {
+	// Op names the client operation, for example NexusClient.AddRepositoryToGroup.
+	Op      string
+	GroupID GroupID
+	// Writes is the number of times the change was written before giving up.
+	Writes int
+	// Wrote is the member list last written; Found is the member list read back after it.
+	Wrote []RepositoryID
+	Found []RepositoryID
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s: repository group %v was modified concurrently and did not converge after %d writes: wrote %v but found %v", e.Op, e.GroupID, e.Writes, e.Wrote, e.Found)
+}
+
+// Unwrap returns ErrConflict.
+func (e *ConflictError) Unwrap() error {
+	return ErrConflict
+}
+
+// ChecksumError is returned when a downloaded file does not match the checksum file the repository manager publishes beside
+// it.  The content has already been written by then and should be discarded.
+type ChecksumError struct {
+	// Op names the client operation, for example NexusClient.DownloadArtifact.
+	Op  string
+	URL string
+	// Algorithm is the extension of the checksum file, sha256 or sha1.
+	Algorithm string
+	Expected  string
+	Actual    string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("%s: %s: %s checksum mismatch: expected %s but got %s", e.Op, e.URL, e.Algorithm, e.Expected, e.Actual)
+}
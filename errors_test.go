@@ -0,0 +1,99 @@
+package maventools
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStatusKind(t *testing.T) {
+	var tests = []struct {
+		statusCode int
+		body       string
+		want       error
+	}{
+		{404, "", ErrNotFound},
+		{401, "", ErrUnauthorized},
+		{403, "", ErrForbidden},
+		{409, "", ErrConflict},
+		{500, "", ErrServerError},
+		{503, "", ErrServerError},
+		{400, "Repository with ID='somerepo' already exists!", ErrAlreadyExists},
+		{400, "Name is already used, must be unique (ignoring case)", ErrAlreadyExists},
+		{400, "malformed", nil},
+	}
+	for _, test := range tests {
+		if got := statusKind(test.statusCode, test.body); got != test.want {
+			t.Fatalf("Want %v but got %v for %d\n", test.want, got, test.statusCode)
+		}
+	}
+}
+
+func TestCreateRepoAlreadyExists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(400)
+		fmt.Fprintf(w, `{"errors":[{"id":"*","msg":"Repository with ID='somerepo' already exists!"}]}`)
+	}))
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	_, err := client.CreateSnapshotRepository("somerepo")
+	if !errors.Is(err, ErrAlreadyExists) {
+		t.Fatalf("Want ErrAlreadyExists but got %v\n", err)
+	}
+
+	var statusError *StatusError
+	if !errors.As(err, &statusError) {
+		t.Fatalf("Want a *StatusError but got %T\n", err)
+	}
+	if statusError.Op != "NexusClient.CreateSnapshotRepository" {
+		t.Fatalf("Want NexusClient.CreateSnapshotRepository but got %s\n", statusError.Op)
+	}
+	if statusError.Method != "POST" {
+		t.Fatalf("Want POST but got %s\n", statusError.Method)
+	}
+	if statusError.URL != server.URL+"/service/local/repositories" {
+		t.Fatalf("Want %s/service/local/repositories but got %s\n", server.URL, statusError.URL)
+	}
+	if statusError.StatusCode != 400 {
+		t.Fatalf("Want 400 but got %d\n", statusError.StatusCode)
+	}
+	if !strings.Contains(statusError.Body, "already exists") {
+		t.Fatalf("Want the response body but got %s\n", statusError.Body)
+	}
+}
+
+func TestDeleteRepoServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	}))
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	_, err := client.DeleteRepository("somerepo")
+	if !errors.Is(err, ErrServerError) {
+		t.Fatalf("Want ErrServerError but got %v\n", err)
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Fatalf("Not expecting ErrNotFound\n")
+	}
+}
+
+func TestRepositoryGroupUnauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(401)
+	}))
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	_, rc, err := client.RepositoryGroup("snapshotgroup")
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("Want ErrUnauthorized but got %v\n", err)
+	}
+	if rc != 401 {
+		t.Fatalf("Want 401 but got %d\n", rc)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package maventools
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+)
+
+// lockGroup reports that NexusClient.GroupLockDir is not supported on this platform.
+func lockGroup(ctx context.Context, dir string, groupID GroupID) (func(), error) {
+	return nil, fmt.Errorf("NexusClient.GroupLockDir: file locks are not supported on %s", runtime.GOOS)
+}
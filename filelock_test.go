@@ -0,0 +1,50 @@
+//go:build unix
+
+package maventools
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGroupLockDir(t *testing.T) {
+	gs, server := newGroupServer(t, "a")
+	defer server.Close()
+
+	dir := t.TempDir()
+	unlock, err := lockGroup(context.Background(), dir, "snapshotgroup")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+
+	// Another process holds the group's lock, so the change must wait for it.
+	client := NewNexusClient(server.URL, "user", "password")
+	client.GroupLockDir = dir
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := client.AddRepositoryToGroupContext(ctx, "somerepo", "snapshotgroup"); err != context.DeadlineExceeded {
+		t.Fatalf("Want %v but got %v\n", context.DeadlineExceeded, err)
+	}
+	if gs.gets != 0 {
+		t.Fatalf("Want no GET but got %d\n", gs.gets)
+	}
+
+	unlock()
+	if _, err := client.AddRepositoryToGroup("somerepo", "snapshotgroup"); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if !isMember("somerepo", gs.group) {
+		t.Fatalf("Want somerepo in the group but it is not\n")
+	}
+
+	// Other groups are not affected by the lock of one.
+	unlock, err = lockGroup(context.Background(), dir, "othergroup")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	defer unlock()
+	if _, err := client.RemoveRepositoryFromGroup("somerepo", "snapshotgroup"); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+}
@@ -0,0 +1,49 @@
+//go:build unix
+
+package maventools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// lockPollInterval is how often lockGroup retries a lock held by another process.
+const lockPollInterval = 50 * time.Millisecond
+
+// lockGroup takes an exclusive flock(2) lock on the file dir/groupID.lock, creating the file if need be, and returns a
+// function that releases it.  The lock is released by the kernel if the process dies while holding it.  While another
+// process holds the lock, lockGroup polls for it until ctx is done.
+func lockGroup(ctx context.Context, dir string, groupID GroupID) (func(), error) {
+	f, err := os.OpenFile(filepath.Join(dir, string(groupID)+".lock"), os.O_RDWR|os.O_CREATE, 0666)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == nil {
+			break
+		}
+		if err != syscall.EWOULDBLOCK && err != syscall.EINTR {
+			f.Close()
+			return nil, &os.PathError{Op: "flock", Path: f.Name(), Err: err}
+		}
+
+		timer := time.NewTimer(lockPollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			f.Close()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return func() {
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
@@ -0,0 +1,37 @@
+package maventools
+
+import (
+	"regexp"
+	"strings"
+)
+
+// RepositoryFilter selects the repositories or groups returned by ListRepositories and ListRepositoryGroups.  Filtering
+// happens in the client, after the server has returned everything.  Zero-valued fields match everything; Policy and Type
+// do not apply to groups.
+type RepositoryFilter struct {
+	IDPrefix  string
+	IDPattern *regexp.Regexp
+	Policy    RepositoryPolicy
+	Type      RepositoryType
+}
+
+func (filter RepositoryFilter) matchesID(id string) bool {
+	if !strings.HasPrefix(id, filter.IDPrefix) {
+		return false
+	}
+	return filter.IDPattern == nil || filter.IDPattern.MatchString(id)
+}
+
+func (filter RepositoryFilter) matchesRepository(repo Repository) bool {
+	if filter.Policy != "" && repo.Policy != filter.Policy {
+		return false
+	}
+	if filter.Type != "" && repo.Type != filter.Type {
+		return false
+	}
+	return filter.matchesID(string(repo.ID))
+}
+
+func (filter RepositoryFilter) matchesGroup(group RepositoryGroup) bool {
+	return filter.matchesID(string(group.ID))
+}
@@ -0,0 +1,185 @@
+package maventools
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type (
+	// groupBatcher coalesces concurrent changes to the members of a repository group.  At most one update of a group runs at
+	// a time; changes that arrive while it runs are queued and then applied together, so a burst of changes costs one
+	// read-verify-write cycle, and one PUT, per group rather than one per change.
+	groupBatcher struct {
+		mu     sync.Mutex
+		groups map[GroupID]*groupQueue
+	}
+
+	// groupQueue holds the state of one group: a token held by the caller running an update of the group and the batch of
+	// changes waiting for the next update.
+	groupQueue struct {
+		running chan struct{}
+		pending *groupBatch
+	}
+
+	groupBatch struct {
+		changes []func([]RepositoryID) ([]RepositoryID, error)
+		// ctxs holds the context of the caller of each change.
+		ctxs []context.Context
+		// errs holds the error of each change as last applied.  A change that fails is left out of the batch without
+		// failing the others.
+		errs []error
+		// changed marks each change that altered the members it was applied to at least once.  Read back after the
+		// write, every change finds itself reflected, so the mark is never cleared.
+		changed []bool
+		started bool
+		done    chan struct{}
+		rc      int
+		err     error
+	}
+)
+
+func newGroupBatcher() *groupBatcher {
+	return &groupBatcher{groups: make(map[GroupID]*groupQueue)}
+}
+
+// do queues change for the group specified by groupID and waits until it has been applied.  The first caller of a batch to
+// find no update of the group running applies the whole batch by calling update with the combined change; the other callers
+// of the batch receive the same response code, except that a caller whose change left the members as they were receives 0,
+// as if it had found nothing to PUT.  The update runs in a context that carries the values of that caller's ctx
+// but ends only when the contexts of all the batch's callers are done, or at the latest of their deadlines.  If ctx is done
+// before the batch starts, change is dropped from it.
+func (b *groupBatcher) do(ctx context.Context, groupID GroupID, change func([]RepositoryID) ([]RepositoryID, error), update func(context.Context, func([]RepositoryID) ([]RepositoryID, error)) (int, error)) (int, error) {
+	b.mu.Lock()
+	queue, ok := b.groups[groupID]
+	if !ok {
+		queue = &groupQueue{running: make(chan struct{}, 1)}
+		b.groups[groupID] = queue
+	}
+	if queue.pending == nil {
+		queue.pending = &groupBatch{done: make(chan struct{})}
+	}
+	batch := queue.pending
+	index := len(batch.changes)
+	batch.changes = append(batch.changes, change)
+	batch.ctxs = append(batch.ctxs, ctx)
+	batch.errs = append(batch.errs, nil)
+	batch.changed = append(batch.changed, false)
+	b.mu.Unlock()
+
+	select {
+	case queue.running <- struct{}{}:
+	case <-batch.done:
+		return batch.result(index)
+	case <-ctx.Done():
+		b.mu.Lock()
+		if !batch.started {
+			batch.changes[index] = nil
+			b.mu.Unlock()
+			return 0, ctx.Err()
+		}
+		b.mu.Unlock()
+		<-batch.done
+		return batch.result(index)
+	}
+
+	b.mu.Lock()
+	if batch.started {
+		// Another caller ran the batch between its completion and this caller taking the token.
+		b.mu.Unlock()
+		<-queue.running
+		<-batch.done
+		return batch.result(index)
+	}
+	batch.started = true
+	queue.pending = nil
+	b.mu.Unlock()
+
+	runCtx, cancel := batch.context(ctx)
+	batch.rc, batch.err = update(runCtx, batch.apply)
+	cancel()
+	close(batch.done)
+
+	b.mu.Lock()
+	<-queue.running
+	if queue.pending == nil {
+		// Every batch of the group has started, so nobody is left to run one; the next change starts afresh.
+		delete(b.groups, groupID)
+	}
+	b.mu.Unlock()
+	return batch.result(index)
+}
+
+// context returns the context in which the batch runs once it has started.  It carries the values of ctx, the context of the
+// caller running the batch, but is cancelled only when the contexts of all the callers still in the batch are done, and its
+// deadline is the latest of theirs.
+func (batch *groupBatch) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	var waiting []context.Context
+	var deadline time.Time
+	bounded := true
+	for i, c := range batch.ctxs {
+		if batch.changes[i] == nil {
+			continue
+		}
+		waiting = append(waiting, c)
+		if d, ok := c.Deadline(); !ok {
+			bounded = false
+		} else if d.After(deadline) {
+			deadline = d
+		}
+	}
+
+	var runCtx context.Context
+	var cancel context.CancelFunc
+	if bounded {
+		runCtx, cancel = context.WithDeadline(context.WithoutCancel(ctx), deadline)
+	} else {
+		runCtx, cancel = context.WithCancel(context.WithoutCancel(ctx))
+	}
+	go func() {
+		expired := bounded
+		for _, c := range waiting {
+			select {
+			case <-c.Done():
+				expired = expired && c.Err() == context.DeadlineExceeded
+			case <-runCtx.Done():
+				return
+			}
+		}
+		if !expired {
+			// When every caller ran out of time, runCtx reaches its deadline too and reports it.
+			cancel()
+		}
+	}()
+	return runCtx, cancel
+}
+
+// apply applies the changes of the batch in the order they were queued.
+func (batch *groupBatch) apply(members []RepositoryID) ([]RepositoryID, error) {
+	for i, change := range batch.changes {
+		if change == nil {
+			continue
+		}
+		result, err := change(members)
+		batch.errs[i] = err
+		if err == nil && !sameMembers(result, members) {
+			batch.changed[i] = true
+		}
+		if err == nil {
+			members = result
+		}
+	}
+	return members, nil
+}
+
+func (batch *groupBatch) result(index int) (int, error) {
+	switch {
+	case batch.err != nil:
+		return batch.rc, batch.err
+	case batch.errs[index] != nil:
+		return batch.rc, batch.errs[index]
+	case !batch.changed[index]:
+		return 0, nil
+	}
+	return batch.rc, nil
+}
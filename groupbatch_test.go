@@ -0,0 +1,365 @@
+package maventools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// queued returns the number of changes waiting for the next update of the group.
+func (b *groupBatcher) queued(groupID GroupID) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if queue := b.groups[groupID]; queue != nil && queue.pending != nil {
+		return len(queue.pending.changes)
+	}
+	return 0
+}
+
+// holdFirstPUT makes the server hold the first PUT until release is closed.  arrived is closed when the PUT arrives.
+func holdFirstPUT(gs *groupServer) (arrived, release chan struct{}) {
+	arrived = make(chan struct{})
+	release = make(chan struct{})
+	gs.beforePUT = func(puts int) {
+		if puts == 1 {
+			close(arrived)
+			gs.Unlock()
+			<-release
+			gs.Lock()
+		}
+	}
+	return arrived, release
+}
+
+func waitQueued(t *testing.T, client NexusClient, groupID GroupID, n int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for client.groups.queued(groupID) != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Want %d queued changes but got %d\n", n, client.groups.queued(groupID))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestAddToGroupCoalesced(t *testing.T) {
+	gs, server := newGroupServer(t, "a")
+	defer server.Close()
+	arrived, release := holdFirstPUT(gs)
+
+	client := NewNexusClient(server.URL, "user", "password")
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	add := func(id RepositoryID) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.AddRepositoryToGroup(id, "snapshotgroup"); err != nil {
+				errs <- err
+			}
+		}()
+	}
+
+	// While the first change is being written, queue four more.
+	add("repo0")
+	<-arrived
+	for i := 1; i < 5; i++ {
+		add(RepositoryID(fmt.Sprintf("repo%d", i)))
+	}
+	waitQueued(t, client, "snapshotgroup", 4)
+	close(release)
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+
+	if gs.puts != 2 {
+		t.Fatalf("Want 2 PUTs but got %d\n", gs.puts)
+	}
+	members := gs.members()
+	sort.Slice(members, func(i, j int) bool { return members[i] < members[j] })
+	if got := fmt.Sprint(members); got != "[a repo0 repo1 repo2 repo3 repo4]" {
+		t.Fatalf("Want [a repo0 repo1 repo2 repo3 repo4] but got %s\n", got)
+	}
+}
+
+func TestCoalescedChangeFails(t *testing.T) {
+	gs, server := newGroupServer(t, "a")
+	defer server.Close()
+	arrived, release := holdFirstPUT(gs)
+
+	client := NewNexusClient(server.URL, "user", "password")
+	var wg sync.WaitGroup
+	var addErr, moveErr error
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		client.AddRepositoryToGroup("repo0", "snapshotgroup")
+	}()
+	<-arrived
+	go func() {
+		defer wg.Done()
+		_, moveErr = client.MoveRepositoryInGroup("notamember", "snapshotgroup", First())
+	}()
+	waitQueued(t, client, "snapshotgroup", 1)
+	go func() {
+		defer wg.Done()
+		_, addErr = client.InsertRepositoryIntoGroup("repo1", "snapshotgroup", First())
+	}()
+	waitQueued(t, client, "snapshotgroup", 2)
+	close(release)
+	wg.Wait()
+
+	if moveErr == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+	if addErr != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", addErr)
+	}
+	if got := fmt.Sprint(gs.members()); got != "[repo1 a repo0]" {
+		t.Fatalf("Want [repo1 a repo0] but got %s\n", got)
+	}
+}
+
+func TestCoalescedNoOpReturnsZero(t *testing.T) {
+	gs, server := newGroupServer(t, "a")
+	defer server.Close()
+	arrived, release := holdFirstPUT(gs)
+
+	logger := &recordingLogger{}
+	client := NewNexusClient(server.URL, "user", "password")
+	client.Logger = logger
+	done := make(chan struct{})
+	go func() {
+		client.AddRepositoryToGroup("repo0", "snapshotgroup")
+		close(done)
+	}()
+	<-arrived
+
+	// The next batch adds y, a member already and z; only the adds of y and z change the group.
+	rcs := make(map[RepositoryID]int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i, id := range []RepositoryID{"y", "a", "z"} {
+		wg.Add(1)
+		go func(id RepositoryID) {
+			defer wg.Done()
+			rc, err := client.AddRepositoryToGroup(id, "snapshotgroup")
+			if err != nil {
+				t.Errorf("Expecting no error but got one: %v\n", err)
+			}
+			mu.Lock()
+			rcs[id] = rc
+			mu.Unlock()
+		}(id)
+		waitQueued(t, client, "snapshotgroup", i+1)
+	}
+	close(release)
+	wg.Wait()
+	<-done
+
+	if rcs["y"] != 200 || rcs["a"] != 0 || rcs["z"] != 200 {
+		t.Fatalf("Want 200 for y and z and 0 for a but got %v\n", rcs)
+	}
+	logged := 0
+	for _, e := range logger.events {
+		if e.msg == "already in repository group; not writing" {
+			if e.fields[FieldRepository] != RepositoryID("a") {
+				t.Fatalf("Want a logged as already in the group but got %v\n", e.fields[FieldRepository])
+			}
+			logged++
+		}
+	}
+	if logged != 1 {
+		t.Fatalf("Want 1 already-in-group event but got %d\n", logged)
+	}
+}
+
+func TestCoalescedChangeCancelled(t *testing.T) {
+	gs, server := newGroupServer(t, "a")
+	defer server.Close()
+	arrived, release := holdFirstPUT(gs)
+
+	client := NewNexusClient(server.URL, "user", "password")
+	done := make(chan struct{})
+	go func() {
+		client.AddRepositoryToGroup("repo0", "snapshotgroup")
+		close(done)
+	}()
+	<-arrived
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan error)
+	go func() {
+		_, err := client.AddRepositoryToGroupContext(ctx, "repo1", "snapshotgroup")
+		cancelled <- err
+	}()
+	waitQueued(t, client, "snapshotgroup", 1)
+	cancel()
+	if err := <-cancelled; err != context.Canceled {
+		t.Fatalf("Want %v but got %v\n", context.Canceled, err)
+	}
+
+	close(release)
+	<-done
+	if got := fmt.Sprint(gs.members()); got != "[a repo0]" {
+		t.Fatalf("Want [a repo0] but got %s\n", got)
+	}
+}
+
+// startBatch holds the update of the group so that the changes queued meanwhile form one batch, and returns a function that
+// lets the update finish.
+func startBatch(t *testing.T, b *groupBatcher, groupID GroupID) func() {
+	started, release := make(chan struct{}), make(chan struct{})
+	go b.do(context.Background(), groupID, nil, func(ctx context.Context, change func([]RepositoryID) ([]RepositoryID, error)) (int, error) {
+		close(started)
+		<-release
+		return 200, nil
+	})
+	<-started
+	return func() { close(release) }
+}
+
+func TestBatchOutlivesCancelledCaller(t *testing.T) {
+	b := newGroupBatcher()
+	release := startBatch(t, b, "snapshotgroup")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	running := make(chan context.Context, 1)
+	finish := make(chan struct{})
+	update := func(ctx context.Context, change func([]RepositoryID) ([]RepositoryID, error)) (int, error) {
+		running <- ctx
+		<-finish
+		return 200, ctx.Err()
+	}
+	errs := make(chan error, 2)
+	for _, c := range []context.Context{ctx, context.Background()} {
+		go func(c context.Context) {
+			_, err := b.do(c, "snapshotgroup", func(m []RepositoryID) ([]RepositoryID, error) { return m, nil }, update)
+			errs <- err
+		}(c)
+	}
+	for b.queued("snapshotgroup") != 2 {
+		time.Sleep(time.Millisecond)
+	}
+	release()
+
+	// Whichever caller runs the batch, cancelling one of them leaves the batch running for the other.
+	runCtx := <-running
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	if runCtx.Err() != nil {
+		t.Fatalf("Want the batch running but got %v\n", runCtx.Err())
+	}
+	close(finish)
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("Expecting no error but got one: %v\n", err)
+		}
+	}
+}
+
+func TestBatchEndsWithItsCallers(t *testing.T) {
+	b := newGroupBatcher()
+	release := startBatch(t, b, "snapshotgroup")
+
+	soon, cancelSoon := context.WithTimeout(context.Background(), time.Minute)
+	defer cancelSoon()
+	later, cancelLater := context.WithTimeout(context.Background(), time.Hour)
+	running := make(chan context.Context, 1)
+	update := func(ctx context.Context, change func([]RepositoryID) ([]RepositoryID, error)) (int, error) {
+		running <- ctx
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+	errs := make(chan error, 2)
+	for _, c := range []context.Context{soon, later} {
+		go func(c context.Context) {
+			_, err := b.do(c, "snapshotgroup", func(m []RepositoryID) ([]RepositoryID, error) { return m, nil }, update)
+			errs <- err
+		}(c)
+	}
+	for b.queued("snapshotgroup") != 2 {
+		time.Sleep(time.Millisecond)
+	}
+	release()
+
+	runCtx := <-running
+	want, _ := later.Deadline()
+	if deadline, ok := runCtx.Deadline(); !ok || !deadline.Equal(want) {
+		t.Fatalf("Want deadline %v but got %v\n", want, deadline)
+	}
+	cancelSoon()
+	cancelLater()
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != context.Canceled {
+			t.Fatalf("Want %v but got %v\n", context.Canceled, err)
+		}
+	}
+}
+
+func TestGroupBatcherForgetsIdleGroups(t *testing.T) {
+	gs, server := newGroupServer(t, "a")
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	if _, err := client.AddRepositoryToGroup("repo0", "snapshotgroup"); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	client.groups.mu.Lock()
+	groups := len(client.groups.groups)
+	client.groups.mu.Unlock()
+	if groups != 0 {
+		t.Fatalf("Want no groups but got %d\n", groups)
+	}
+	if got := fmt.Sprint(gs.members()); got != "[a repo0]" {
+		t.Fatalf("Want [a repo0] but got %s\n", got)
+	}
+}
+
+func TestStructLiteralClientsDoNotShareBatches(t *testing.T) {
+	gs, server := newGroupServer(t, "a")
+	defer server.Close()
+	arrived, release := holdFirstPUT(gs)
+	var mu sync.Mutex
+	puts := make(map[string][]RepositoryID)
+	handler := server.Config.Handler
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r)
+		if r.Method == "PUT" {
+			mu.Lock()
+			puts[r.Header.Get("Authorization")] = gs.members()
+			mu.Unlock()
+		}
+	})
+
+	newClient := func(token string) NexusClient {
+		return NexusClient{ClientConfig: ClientConfig{BaseURL: server.URL, HttpClient: &http.Client{}, Authenticator: BearerToken(token)}}
+	}
+	done := make(chan error)
+	go func() {
+		_, err := newClient("alice").AddRepositoryToGroup("alicerepo", "snapshotgroup")
+		done <- err
+	}()
+	<-arrived
+
+	// While alice's PUT is held, bob's change is written by bob rather than queued behind it.
+	if _, err := newClient("bob").AddRepositoryToGroup("bobrepo", "snapshotgroup"); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if got := fmt.Sprint(puts["Bearer alice"]); got != "[a alicerepo]" {
+		t.Fatalf("Want alice to PUT [a alicerepo] but got %s\n", got)
+	}
+	if got := fmt.Sprint(puts["Bearer bob"]); got != "[a bobrepo]" {
+		t.Fatalf("Want bob to PUT [a bobrepo] but got %s\n", got)
+	}
+}
@@ -2,6 +2,16 @@ package maventools
 
 import "testing"
 
+// isMember reports whether repositoryID is among the member IDs of group.
+func isMember(repositoryID RepositoryID, group repoGroup) bool {
+	for _, id := range memberIDs(group) {
+		if id == repositoryID {
+			return true
+		}
+	}
+	return false
+}
+
 func TestGroupMembership(t *testing.T) {
 	ra := []repository{
 		{ID: "foo", Name: "foo", ResourceURI: "blah"},
@@ -9,24 +19,22 @@ func TestGroupMembership(t *testing.T) {
 	}
 	group := repoGroup{Data: RepositoryGroupData{Repositories: ra}}
 
-	present := repoIsInGroup("foo", group)
-	if !present {
+	if !isMember("foo", group) {
 		t.Fatalf("Wanted true but got false\n")
 	}
-
-	absent := repoIsNotInGroup("baz", group)
-	if !absent {
-		t.Fatalf("Wanted true but got false\n")
+	if isMember("baz", group) {
+		t.Fatalf("Wanted false but got true\n")
 	}
 
-	removeRepo("foo", &group)
-	if len(group.Data.Repositories) != 1 {
-		t.Fatalf("Wanted 1 but got %d\n", len(group.Data.Repositories))
+	change, _, err := MembershipChange{Remove: []RepositoryID{"foo"}}.apply(nil)
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
 	}
-	if repoIsInGroup("foo", group) {
-		t.Fatalf("Wanted false but got true\n")
+	members, err := change(memberIDs(group))
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
 	}
-	if repoIsNotInGroup("bar", group) {
-		t.Fatalf("Wanted false but got true\n")
+	if len(members) != 1 || members[0] != "bar" {
+		t.Fatalf("Wanted [bar] but got %v\n", members)
 	}
 }
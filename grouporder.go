@@ -0,0 +1,171 @@
+package maventools
+
+import (
+	"context"
+	"fmt"
+)
+
+// GroupPosition says where in a repository group's ordered member list a repository goes.  Maven consults the members of a
+// group in order, so the position decides which repository wins when several hold the same artifact.
+type GroupPosition struct {
+	index  int
+	last   bool
+	before RepositoryID
+	after  RepositoryID
+}
+
+// AtIndex places a repository at index i of the member list, where 0 is the first position and the current number of
+// members is the last.  An index outside that range, negative ones included, is an error.
+func AtIndex(i int) GroupPosition {
+	return GroupPosition{index: i}
+}
+
+// First places a repository ahead of all other members.
+func First() GroupPosition {
+	return AtIndex(0)
+}
+
+// Last places a repository behind all other members, which is where AddRepositoryToGroup puts it.
+func Last() GroupPosition {
+	return GroupPosition{last: true}
+}
+
+// Before places a repository immediately ahead of the member repositoryID.
+func Before(repositoryID RepositoryID) GroupPosition {
+	return GroupPosition{before: repositoryID}
+}
+
+// After places a repository immediately behind the member repositoryID.
+func After(repositoryID RepositoryID) GroupPosition {
+	return GroupPosition{after: repositoryID}
+}
+
+// InsertRepositoryIntoGroup adds the given repository specified by repositoryID to the repository group specified by groupID at
+// the given position.  As with AddRepositoryToGroup, nothing is PUT if the repository is already in the group; use
+// MoveRepositoryInGroup to change the position of an existing member.
+//
+// InsertRepositoryIntoGroup uses context.Background internally; to specify the context, use InsertRepositoryIntoGroupContext.
+func (client NexusClient) InsertRepositoryIntoGroup(repositoryID RepositoryID, groupID GroupID, position GroupPosition) (int, error) {
+	return client.InsertRepositoryIntoGroupContext(context.Background(), repositoryID, groupID, position)
+}
+
+// InsertRepositoryIntoGroupContext is like InsertRepositoryIntoGroup but uses ctx for its HTTP requests and any pending retries.
+func (client NexusClient) InsertRepositoryIntoGroupContext(ctx context.Context, repositoryID RepositoryID, groupID GroupID, position GroupPosition) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "NexusClient.InsertRepositoryIntoGroup", repositoryAttribute(repositoryID), groupAttribute(groupID))
+	defer func() { span.end(err) }()
+
+	insert := func(members []RepositoryID) ([]RepositoryID, error) {
+		for _, id := range members {
+			if id == repositoryID {
+				return members, nil
+			}
+		}
+		return insertMember(members, repositoryID, position)
+	}
+	rc, err := client.updateGroup(ctx, "NexusClient.InsertRepositoryIntoGroup", groupID, insert)
+	if err == nil && rc == 0 {
+		client.logAlreadyInGroup(ctx, "NexusClient.InsertRepositoryIntoGroup", repositoryID, groupID)
+	}
+	return rc, err
+}
+
+// MoveRepositoryInGroup moves the repository specified by repositoryID, which must already be a member of the repository
+// group specified by groupID, to the given position.  Positions are interpreted as if the repository had first been
+// removed from the group.
+//
+// MoveRepositoryInGroup uses context.Background internally; to specify the context, use MoveRepositoryInGroupContext.
+func (client NexusClient) MoveRepositoryInGroup(repositoryID RepositoryID, groupID GroupID, position GroupPosition) (int, error) {
+	return client.MoveRepositoryInGroupContext(context.Background(), repositoryID, groupID, position)
+}
+
+// MoveRepositoryInGroupContext is like MoveRepositoryInGroup but uses ctx for its HTTP requests and any pending retries.
+func (client NexusClient) MoveRepositoryInGroupContext(ctx context.Context, repositoryID RepositoryID, groupID GroupID, position GroupPosition) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "NexusClient.MoveRepositoryInGroup", repositoryAttribute(repositoryID), groupAttribute(groupID))
+	defer func() { span.end(err) }()
+
+	move := func(members []RepositoryID) ([]RepositoryID, error) {
+		others := make([]RepositoryID, 0, len(members))
+		for _, id := range members {
+			if id != repositoryID {
+				others = append(others, id)
+			}
+		}
+		if len(others) == len(members) {
+			return nil, fmt.Errorf("NexusClient.MoveRepositoryInGroup(): RepositoryID %v is not in repository group %v", repositoryID, groupID)
+		}
+		return insertMember(others, repositoryID, position)
+	}
+	return client.updateGroup(ctx, "NexusClient.MoveRepositoryInGroup", groupID, move)
+}
+
+// SetGroupRepositories replaces the members of the repository group specified by groupID with repositories, in the given
+// order, using a single PUT.  Members not listed are removed from the group.
+//
+// SetGroupRepositories uses context.Background internally; to specify the context, use SetGroupRepositoriesContext.
+func (client NexusClient) SetGroupRepositories(groupID GroupID, repositories []RepositoryID) (int, error) {
+	return client.SetGroupRepositoriesContext(context.Background(), groupID, repositories)
+}
+
+// SetGroupRepositoriesContext is like SetGroupRepositories but uses ctx for its HTTP requests and any pending retries.
+func (client NexusClient) SetGroupRepositoriesContext(ctx context.Context, groupID GroupID, repositories []RepositoryID) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "NexusClient.SetGroupRepositories", groupAttribute(groupID), repositoriesAttribute(AttributeRepositoryIDs, repositories))
+	defer func() { span.end(err) }()
+
+	seen := make(map[RepositoryID]bool)
+	for _, id := range repositories {
+		if seen[id] {
+			return 0, fmt.Errorf("NexusClient.SetGroupRepositories(): RepositoryID %v is listed more than once", id)
+		}
+		seen[id] = true
+	}
+
+	replace := func([]RepositoryID) ([]RepositoryID, error) {
+		return repositories, nil
+	}
+	return client.updateGroup(ctx, "NexusClient.SetGroupRepositories", groupID, replace)
+}
+
+// insertMember returns a copy of members with repositoryID inserted at position.
+func insertMember(members []RepositoryID, repositoryID RepositoryID, position GroupPosition) ([]RepositoryID, error) {
+	index := position.index
+	switch {
+	case position.last:
+		index = len(members)
+	case position.before != "" || position.after != "":
+		anchor := position.before
+		if anchor == "" {
+			anchor = position.after
+		}
+		index = -1
+		for i, id := range members {
+			if id == anchor {
+				index = i
+			}
+		}
+		if index == -1 {
+			return nil, fmt.Errorf("RepositoryID %v is not in the repository group", anchor)
+		}
+		if position.after != "" {
+			index++
+		}
+	case index < 0 || index > len(members):
+		return nil, fmt.Errorf("position %d is outside a repository group of %d members", index, len(members))
+	}
+
+	result := make([]RepositoryID, 0, len(members)+1)
+	result = append(result, members[:index]...)
+	result = append(result, repositoryID)
+	return append(result, members[index:]...), nil
+}
+
+func sameMembers(a, b []RepositoryID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,209 @@
+package maventools
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var orderedGroup string = `{
+   "data" : {
+      "provider" : "maven2",
+      "name" : "SnapshotGroup",
+      "repositories" : [
+         { "name" : "a", "id" : "a", "resourceURI" : "http://localhost:8081/nexus/service/local/repo_groups/snapshotgroup/a" },
+         { "name" : "b", "id" : "b", "resourceURI" : "http://localhost:8081/nexus/service/local/repo_groups/snapshotgroup/b" },
+         { "name" : "c", "id" : "c", "resourceURI" : "http://localhost:8081/nexus/service/local/repo_groups/snapshotgroup/c" }
+      ],
+      "format" : "maven2",
+      "repoType" : "group",
+      "exposed" : true,
+      "id" : "snapshotgroup",
+      "contentResourceURI" : "http://localhost:8081/nexus/content/groups/snapshotgroup"
+   }
+}`
+
+// orderServer serves orderedGroup, or whatever was last PUT, and records the member IDs of each PUT.
+func orderServer(t *testing.T, puts *[][]RepositoryID) *httptest.Server {
+	var written []byte
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			if written != nil {
+				fmt.Fprintf(w, "%s", written)
+				return
+			}
+			fmt.Fprintf(w, "%s", orderedGroup)
+			return
+		}
+		if r.Method != "PUT" {
+			t.Fatalf("Wanted GET or PUT but got %s\n", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/service/local/repo_groups/snapshotgroup") {
+			t.Fatalf("Wanted URL suffix /service/local/repo_groups/snapshotgroup but got: %s\n", r.URL.Path)
+		}
+
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+		written = data
+		var repogroup repoGroup
+		if err := json.Unmarshal(data, &repogroup); err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+		*puts = append(*puts, memberIDs(repogroup))
+		w.WriteHeader(200)
+	}))
+}
+
+func TestInsertRepositoryIntoGroup(t *testing.T) {
+	var tests = []struct {
+		position GroupPosition
+		want     string
+	}{
+		{First(), "[x a b c]"},
+		{Last(), "[a b c x]"},
+		{AtIndex(1), "[a x b c]"},
+		{AtIndex(3), "[a b c x]"},
+		{Before("c"), "[a b x c]"},
+		{After("a"), "[a x b c]"},
+		{After("c"), "[a b c x]"},
+	}
+	for _, test := range tests {
+		var puts [][]RepositoryID
+		server := orderServer(t, &puts)
+
+		client := NewNexusClient(server.URL, "user", "password")
+		rc, err := client.InsertRepositoryIntoGroup("x", "snapshotgroup", test.position)
+		server.Close()
+		if err != nil {
+			t.Fatalf("Expecting no error but got one: %v\n", err)
+		}
+		if rc != 200 {
+			t.Fatalf("Want 200 but got %d\n", rc)
+		}
+		if len(puts) != 1 {
+			t.Fatalf("Want 1 PUT but got %d\n", len(puts))
+		}
+		if got := fmt.Sprint(puts[0]); got != test.want {
+			t.Fatalf("Want %s but got %s\n", test.want, got)
+		}
+	}
+}
+
+func TestInsertRepositoryIntoGroupBadPosition(t *testing.T) {
+	for _, position := range []GroupPosition{AtIndex(4), AtIndex(-1), AtIndex(-2), Before("nosuchrepo")} {
+		var puts [][]RepositoryID
+		server := orderServer(t, &puts)
+
+		client := NewNexusClient(server.URL, "user", "password")
+		_, err := client.InsertRepositoryIntoGroup("x", "snapshotgroup", position)
+		server.Close()
+		if err == nil {
+			t.Fatalf("Expecting an error but did not get one for %+v\n", position)
+		}
+		if len(puts) != 0 {
+			t.Fatalf("Want no PUT but got %d\n", len(puts))
+		}
+	}
+}
+
+func TestInsertRepositoryAlreadyInGroup(t *testing.T) {
+	var puts [][]RepositoryID
+	server := orderServer(t, &puts)
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	rc, err := client.InsertRepositoryIntoGroup("b", "snapshotgroup", First())
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 0 || len(puts) != 0 {
+		t.Fatalf("Want no PUT but got %d (rc %d)\n", len(puts), rc)
+	}
+}
+
+func TestMoveRepositoryInGroup(t *testing.T) {
+	var tests = []struct {
+		id       RepositoryID
+		position GroupPosition
+		want     string
+	}{
+		{"c", First(), "[c a b]"},
+		{"a", Last(), "[b c a]"},
+		{"a", After("b"), "[b a c]"},
+		{"c", Before("b"), "[a c b]"},
+		{"b", AtIndex(0), "[b a c]"},
+	}
+	for _, test := range tests {
+		var puts [][]RepositoryID
+		server := orderServer(t, &puts)
+
+		client := NewNexusClient(server.URL, "user", "password")
+		_, err := client.MoveRepositoryInGroup(test.id, "snapshotgroup", test.position)
+		server.Close()
+		if err != nil {
+			t.Fatalf("Expecting no error but got one: %v\n", err)
+		}
+		if len(puts) != 1 {
+			t.Fatalf("Want 1 PUT but got %d\n", len(puts))
+		}
+		if got := fmt.Sprint(puts[0]); got != test.want {
+			t.Fatalf("Want %s but got %s\n", test.want, got)
+		}
+	}
+}
+
+func TestMoveRepositoryInGroupUnchanged(t *testing.T) {
+	var puts [][]RepositoryID
+	server := orderServer(t, &puts)
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	if _, err := client.MoveRepositoryInGroup("a", "snapshotgroup", First()); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if len(puts) != 0 {
+		t.Fatalf("Want no PUT but got %d\n", len(puts))
+	}
+}
+
+func TestMoveRepositoryNotInGroup(t *testing.T) {
+	var puts [][]RepositoryID
+	server := orderServer(t, &puts)
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	if _, err := client.MoveRepositoryInGroup("x", "snapshotgroup", First()); err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+}
+
+func TestSetGroupRepositories(t *testing.T) {
+	var puts [][]RepositoryID
+	server := orderServer(t, &puts)
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	rc, err := client.SetGroupRepositories("snapshotgroup", []RepositoryID{"c", "x", "a"})
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 200 {
+		t.Fatalf("Want 200 but got %d\n", rc)
+	}
+	if len(puts) != 1 {
+		t.Fatalf("Want 1 PUT but got %d\n", len(puts))
+	}
+	if got := fmt.Sprint(puts[0]); got != "[c x a]" {
+		t.Fatalf("Want [c x a] but got %s\n", got)
+	}
+
+	if _, err := client.SetGroupRepositories("snapshotgroup", []RepositoryID{"a", "a"}); err == nil {
+		t.Fatalf("Expecting an error for a duplicate member but did not get one\n")
+	}
+}
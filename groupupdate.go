@@ -0,0 +1,99 @@
+package maventools
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// maxGroupWrites bounds how many times a change to the members of a repository group is written before it is reported as a
+// *ConflictError.
+const maxGroupWrites = 5
+
+// updateGroupMembers changes the ordered member list of a repository group with a read-verify-write loop.  None of the
+// supported repository managers can make a group update conditional on the version that was read, so a writer racing with
+// another can silently undo the other's change.  To detect that, the group is read back after every write: change is
+// applied to the members found, and if that would alter them again, the change was lost to a concurrent writer and is
+// written once more, after a short randomized pause.  The loop gives up with a *ConflictError after maxGroupWrites writes.
+//
+// change must be idempotent: applied to a member list that already reflects it, it must return that list unchanged.  A
+// writer whose change is undone by this client's write notices it in the same way, so cooperating writers converge.  The
+// loop narrows rather than closes the window for lost updates, though: a change undone after its writer has read it back
+// and returned goes unnoticed.
+//
+// read returns the current members and the HTTP response code; write stores the given members and returns the HTTP response
+// code.  The integer return value is the response code of the last write, or 0 if the group already reflected the change
+// and nothing was written.
+func (config ClientConfig) updateGroupMembers(ctx context.Context, op string, groupID GroupID, read func() ([]RepositoryID, int, error), write func([]RepositoryID) (int, error), change func([]RepositoryID) ([]RepositoryID, error)) (int, error) {
+	var responseCode int
+	var written []RepositoryID
+	for writes := 0; ; writes++ {
+		current, rc, err := read()
+		if err != nil {
+			return rc, err
+		}
+
+		members, err := change(current)
+		if err != nil {
+			return responseCode, err
+		}
+		if sameMembers(current, members) {
+			return responseCode, nil
+		}
+
+		if writes > 0 {
+			if writes == maxGroupWrites {
+				return responseCode, &ConflictError{Op: op, GroupID: groupID, Writes: writes, Wrote: written, Found: current}
+			}
+			config.logger().Log(ctx, LevelWarn, "repository group modified concurrently; writing again", Field{FieldOp, op}, Field{FieldGroup, groupID},
+				Field{FieldMembers, current}, Field{FieldWritten, written})
+			if err := conflictPause(ctx, writes); err != nil {
+				return responseCode, err
+			}
+		}
+
+		responseCode, err = write(members)
+		if err != nil {
+			return responseCode, err
+		}
+		written = members
+	}
+}
+
+// conflictPause waits a random time of up to 100ms, doubled for each write after the first, so that writers which keep
+// undoing each other's changes fall out of step.  It returns early with the context's error if ctx is done.
+func conflictPause(ctx context.Context, writes int) error {
+	timer := time.NewTimer(time.Duration(rand.Int63n(int64((100 * time.Millisecond) << uint(writes)))))
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// withMember returns a change that appends repositoryID to a member list that does not already contain it.
+func withMember(repositoryID RepositoryID) func([]RepositoryID) ([]RepositoryID, error) {
+	return func(members []RepositoryID) ([]RepositoryID, error) {
+		for _, id := range members {
+			if id == repositoryID {
+				return members, nil
+			}
+		}
+		return insertMember(members, repositoryID, Last())
+	}
+}
+
+// withoutMember returns a change that removes repositoryID from a member list.
+func withoutMember(repositoryID RepositoryID) func([]RepositoryID) ([]RepositoryID, error) {
+	return func(members []RepositoryID) ([]RepositoryID, error) {
+		result := make([]RepositoryID, 0, len(members))
+		for _, id := range members {
+			if id != repositoryID {
+				result = append(result, id)
+			}
+		}
+		return result, nil
+	}
+}
@@ -0,0 +1,271 @@
+package maventools
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// groupServer is a Nexus 2 repository group endpoint that keeps the group in memory.  It lists repositories and groups as
+// existing.  The hooks, when set, run with the lock held and may modify the group to simulate other writers.
+type groupServer struct {
+	sync.Mutex
+	group        repoGroup
+	repositories []RepositoryID
+	groups       []GroupID
+	gets         int
+	puts         int
+	beforeGET    func(gets int)
+	beforePUT    func(puts int)
+	afterPUT     func(puts int)
+}
+
+func newGroupServer(t *testing.T, members ...RepositoryID) (*groupServer, *httptest.Server) {
+	gs := &groupServer{}
+	gs.group.Data.ID = "snapshotgroup"
+	gs.repositories = append(gs.repositories, members...)
+	for _, id := range members {
+		gs.group.Data.Repositories = append(gs.group.Data.Repositories, repository{ID: id, Name: string(id)})
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/service/local/repositories":
+			var list repoList
+			gs.Lock()
+			for _, id := range gs.repositories {
+				list.Data = append(list.Data, repositoryListItem{ID: id, Name: string(id)})
+			}
+			gs.Unlock()
+			if err := json.NewEncoder(w).Encode(&list); err != nil {
+				t.Fatalf("Not expecting an error but got one: %v\n", err)
+			}
+		case r.Method == "GET" && r.URL.Path == "/service/local/repo_groups":
+			var list repoGroupList
+			gs.Lock()
+			for _, id := range gs.groups {
+				list.Data = append(list.Data, RepositoryGroupData{ID: id, Name: string(id)})
+			}
+			gs.Unlock()
+			if err := json.NewEncoder(w).Encode(&list); err != nil {
+				t.Fatalf("Not expecting an error but got one: %v\n", err)
+			}
+		case r.Method == "GET":
+			gs.Lock()
+			gs.gets++
+			if gs.beforeGET != nil {
+				gs.beforeGET(gs.gets)
+			}
+			data, err := json.Marshal(&gs.group)
+			gs.Unlock()
+			if err != nil {
+				t.Fatalf("Not expecting an error but got one: %v\n", err)
+			}
+			fmt.Fprintf(w, "%s", data)
+		case r.Method == "PUT":
+			data, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				t.Fatalf("Not expecting an error but got one: %v\n", err)
+			}
+			var repogroup repoGroup
+			if err := json.Unmarshal(data, &repogroup); err != nil {
+				t.Fatalf("Not expecting an error but got one: %v\n", err)
+			}
+			gs.Lock()
+			gs.puts++
+			if gs.beforePUT != nil {
+				gs.beforePUT(gs.puts)
+			}
+			gs.group = repogroup
+			if gs.afterPUT != nil {
+				gs.afterPUT(gs.puts)
+			}
+			gs.Unlock()
+			w.WriteHeader(200)
+		default:
+			t.Fatalf("Wanted GET or PUT but got %s\n", r.Method)
+		}
+	}))
+	return gs, server
+}
+
+// barrier returns a hook that holds the first n requests until all n have arrived.
+func (gs *groupServer) barrier(n int) func(int) {
+	var arrived sync.WaitGroup
+	arrived.Add(n)
+	return func(count int) {
+		if count <= n {
+			arrived.Done()
+			gs.Unlock()
+			arrived.Wait()
+			gs.Lock()
+		}
+	}
+}
+
+func (gs *groupServer) members() []RepositoryID {
+	gs.Lock()
+	defer gs.Unlock()
+	return memberIDs(gs.group)
+}
+
+func TestAddToGroupLostUpdate(t *testing.T) {
+	gs, server := newGroupServer(t, "a")
+	defer server.Close()
+
+	// Another writer read the group before our PUT and writes its own change right after it, dropping ours.
+	gs.afterPUT = func(puts int) {
+		if puts == 1 {
+			gs.group.Data.Repositories = []repository{{ID: "a", Name: "a"}, {ID: "other", Name: "other"}}
+		}
+	}
+
+	client := NewNexusClient(server.URL, "user", "password")
+	rc, err := client.AddRepositoryToGroup("somerepo", "snapshotgroup")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 200 {
+		t.Fatalf("Want 200 but got %d\n", rc)
+	}
+	if gs.puts != 2 {
+		t.Fatalf("Want 2 PUTs but got %d\n", gs.puts)
+	}
+	if got := fmt.Sprint(gs.members()); got != "[a other somerepo]" {
+		t.Fatalf("Want [a other somerepo] but got %s\n", got)
+	}
+}
+
+func TestRemoveFromGroupLostUpdate(t *testing.T) {
+	gs, server := newGroupServer(t, "a", "b")
+	defer server.Close()
+
+	gs.afterPUT = func(puts int) {
+		if puts == 1 {
+			gs.group.Data.Repositories = []repository{{ID: "a", Name: "a"}, {ID: "b", Name: "b"}, {ID: "other", Name: "other"}}
+		}
+	}
+
+	client := NewNexusClient(server.URL, "user", "password")
+	if _, err := client.RemoveRepositoryFromGroup("b", "snapshotgroup"); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if got := fmt.Sprint(gs.members()); got != "[a other]" {
+		t.Fatalf("Want [a other] but got %s\n", got)
+	}
+}
+
+func TestConcurrentAddToGroup(t *testing.T) {
+	gs, server := newGroupServer(t, "a")
+	defer server.Close()
+
+	// Both writers read the group before either writes, and both write before either reads it back, so the first PUT is
+	// lost to the second.
+	gs.beforeGET = gs.barrier(2)
+	gs.beforePUT = gs.barrier(2)
+
+	// Separate clients, as a client coalesces its own concurrent changes.
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	for _, id := range []RepositoryID{"repo1", "repo2"} {
+		wg.Add(1)
+		go func(id RepositoryID) {
+			defer wg.Done()
+			client := NewNexusClient(server.URL, "user", "password")
+			if _, err := client.AddRepositoryToGroup(id, "snapshotgroup"); err != nil {
+				errs <- err
+			}
+		}(id)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+
+	members := gs.members()
+	sort.Slice(members, func(i, j int) bool { return members[i] < members[j] })
+	if got := fmt.Sprint(members); got != "[a repo1 repo2]" {
+		t.Fatalf("Want [a repo1 repo2] but got %s\n", got)
+	}
+	if gs.puts != 3 {
+		t.Fatalf("Want 3 PUTs but got %d\n", gs.puts)
+	}
+}
+
+func TestAddToGroupDoesNotConverge(t *testing.T) {
+	gs, server := newGroupServer(t, "a")
+	defer server.Close()
+
+	// A writer that undoes every change.
+	gs.afterPUT = func(puts int) {
+		gs.group.Data.Repositories = []repository{{ID: "a", Name: "a"}}
+	}
+
+	client := NewNexusClient(server.URL, "user", "password")
+	_, err := client.AddRepositoryToGroup("somerepo", "snapshotgroup")
+	if err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("Want ErrConflict but got %v\n", err)
+	}
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("Want a *ConflictError but got %T\n", err)
+	}
+	if conflict.Writes != maxGroupWrites {
+		t.Fatalf("Want %d but got %d\n", maxGroupWrites, conflict.Writes)
+	}
+	if fmt.Sprint(conflict.Wrote) != "[a somerepo]" || fmt.Sprint(conflict.Found) != "[a]" {
+		t.Fatalf("Want [a somerepo] and [a] but got %v and %v\n", conflict.Wrote, conflict.Found)
+	}
+	if gs.puts != maxGroupWrites {
+		t.Fatalf("Want %d PUTs but got %d\n", maxGroupWrites, gs.puts)
+	}
+}
+
+func TestNexus3AddToGroupLostUpdate(t *testing.T) {
+	var written []byte
+	puts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			if written != nil {
+				fmt.Fprintf(w, "%s", written)
+				return
+			}
+			fmt.Fprintf(w, "%s", nexus3GroupJSON)
+			return
+		}
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+		written = data
+		puts++
+		if puts == 1 {
+			// Another writer's change, made from the member list read before our PUT.
+			written = []byte(`{"name":"snapshotgroup","online":true,"group":{"memberNames":["plat.trnk.trnk679","other"]}}`)
+		}
+		w.WriteHeader(204)
+	}))
+	defer server.Close()
+
+	client := NewNexus3Client(server.URL, "user", "password")
+	if _, err := client.AddRepositoryToGroup("somerepo", "snapshotgroup"); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	var group nexus3GroupRepo
+	if err := json.Unmarshal(written, &group); err != nil {
+		t.Fatalf("Not expecting an error but got one: %v\n", err)
+	}
+	if got := fmt.Sprint(group.Group.MemberNames); got != "[plat.trnk.trnk679 other somerepo]" {
+		t.Fatalf("Want [plat.trnk.trnk679 other somerepo] but got %s\n", got)
+	}
+}
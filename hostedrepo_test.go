@@ -0,0 +1,116 @@
+package maventools
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateHostedRepo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("Got an error but was not expecting one: %v\n", err)
+		}
+
+		var repo createrepo
+		if err := xml.Unmarshal(b, &repo); err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+
+		if repo.Data.Id != "releases" {
+			t.Fatalf("Want releases but got %v\n", repo.Data.Id)
+		}
+		if repo.Data.Name != "Team Releases" {
+			t.Fatalf("Want Team Releases but got %v\n", repo.Data.Name)
+		}
+		if repo.Data.RepoPolicy != "RELEASE" {
+			t.Fatalf("Want RELEASE but got %v\n", repo.Data.RepoPolicy)
+		}
+		if repo.Data.WritePolicy != "ALLOW_WRITE_ONCE" {
+			t.Fatalf("Want ALLOW_WRITE_ONCE but got %v\n", repo.Data.WritePolicy)
+		}
+		if repo.Data.Browseable {
+			t.Fatalf("Want false but got true\n")
+		}
+		if !repo.Data.Indexable {
+			t.Fatalf("Want true but got false\n")
+		}
+		if repo.Data.NotFoundCacheTTL != 60 {
+			t.Fatalf("Want 60 but got %d\n", repo.Data.NotFoundCacheTTL)
+		}
+
+		w.WriteHeader(201)
+	}))
+	defer server.Close()
+
+	options := NewHostedRepositoryOptions("releases", ReleasePolicy)
+	options.Name = "Team Releases"
+	options.WritePolicy = AllowWriteOnce
+	options.Browseable = false
+	options.NotFoundCacheTTL = 60
+
+	client := NewNexusClient(server.URL, "user", "password")
+	rc, err := client.CreateHostedRepository(options)
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 201 {
+		t.Fatalf("Want 201 but got %d\n", rc)
+	}
+}
+
+func TestCreateReleaseRepo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("Got an error but was not expecting one: %v\n", err)
+		}
+
+		var repo createrepo
+		if err := xml.Unmarshal(b, &repo); err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+		if repo.Data.Id != "somerepo" {
+			t.Fatalf("Want somerepo but got %v\n", repo.Data.Id)
+		}
+		if repo.Data.RepoPolicy != "RELEASE" {
+			t.Fatalf("Want RELEASE but got %v\n", repo.Data.RepoPolicy)
+		}
+		if repo.Data.WritePolicy != "ALLOW_WRITE" {
+			t.Fatalf("Want ALLOW_WRITE but got %v\n", repo.Data.WritePolicy)
+		}
+		w.WriteHeader(201)
+	}))
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	if _, err := client.CreateReleaseRepository("somerepo"); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+}
+
+func TestHostedRepositoryOptionsDefaults(t *testing.T) {
+	options, err := HostedRepositoryOptions{ID: "somerepo", Policy: MixedPolicy}.withDefaults()
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if options.Name != "somerepo" {
+		t.Fatalf("Want somerepo but got %v\n", options.Name)
+	}
+	if options.WritePolicy != AllowWrite {
+		t.Fatalf("Want ALLOW_WRITE but got %v\n", options.WritePolicy)
+	}
+
+	if _, err := (HostedRepositoryOptions{ID: "somerepo"}).withDefaults(); err == nil {
+		t.Fatalf("Expecting an error for a missing policy but did not get one\n")
+	}
+	if _, err := (HostedRepositoryOptions{Policy: ReleasePolicy}).withDefaults(); err == nil {
+		t.Fatalf("Expecting an error for a missing ID but did not get one\n")
+	}
+	if _, err := (HostedRepositoryOptions{ID: "somerepo", Policy: ReleasePolicy, WritePolicy: "SOMETIMES"}).withDefaults(); err == nil {
+		t.Fatalf("Expecting an error for an unknown write policy but did not get one\n")
+	}
+}
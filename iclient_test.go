@@ -0,0 +1,27 @@
+package maventools
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// Because ClientConfig embeds IClient, a client that forgets to implement an IClient method still compiles, and calling the
+// method panics on the nil embedded interface.  Promoted methods are compiler-generated wrappers, which this test detects.
+func TestClientsImplementIClient(t *testing.T) {
+	iclient := reflect.TypeOf((*IClient)(nil)).Elem()
+	for _, client := range []IClient{NexusClient{}, Nexus3Client{}, ArtifactoryClient{}} {
+		clientType := reflect.TypeOf(client)
+		for i := 0; i < iclient.NumMethod(); i++ {
+			name := iclient.Method(i).Name
+			method, ok := clientType.MethodByName(name)
+			if !ok {
+				t.Fatalf("%v has no method %s\n", clientType, name)
+			}
+			pc := method.Func.Pointer()
+			if file, _ := runtime.FuncForPC(pc).FileLine(pc); file == "<autogenerated>" {
+				t.Fatalf("%v does not implement %s\n", clientType, name)
+			}
+		}
+	}
+}
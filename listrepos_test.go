@@ -0,0 +1,202 @@
+package maventools
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var repositories string = `
+{
+   "data" : [
+      {
+         "resourceURI" : "http://localhost:8081/nexus/service/local/repositories/releases",
+         "contentResourceURI" : "http://localhost:8081/nexus/content/repositories/releases",
+         "id" : "releases",
+         "name" : "Releases",
+         "repoType" : "hosted",
+         "repoPolicy" : "RELEASE",
+         "provider" : "maven2",
+         "format" : "maven2",
+         "exposed" : true
+      },
+      {
+         "resourceURI" : "http://localhost:8081/nexus/service/local/repositories/plat.trnk.trnk679",
+         "contentResourceURI" : "http://localhost:8081/nexus/content/repositories/plat.trnk.trnk679",
+         "id" : "plat.trnk.trnk679",
+         "name" : "plat.trnk.trnk679",
+         "repoType" : "hosted",
+         "repoPolicy" : "SNAPSHOT",
+         "provider" : "maven2",
+         "format" : "maven2",
+         "exposed" : true
+      },
+      {
+         "resourceURI" : "http://localhost:8081/nexus/service/local/repositories/plat.trnk.trnk680",
+         "contentResourceURI" : "http://localhost:8081/nexus/content/repositories/plat.trnk.trnk680",
+         "id" : "plat.trnk.trnk680",
+         "name" : "plat.trnk.trnk680",
+         "repoType" : "hosted",
+         "repoPolicy" : "SNAPSHOT",
+         "provider" : "maven2",
+         "format" : "maven2",
+         "exposed" : true
+      },
+      {
+         "resourceURI" : "http://localhost:8081/nexus/service/local/repositories/central",
+         "contentResourceURI" : "http://localhost:8081/nexus/content/repositories/central",
+         "id" : "central",
+         "name" : "Maven Central",
+         "repoType" : "proxy",
+         "repoPolicy" : "RELEASE",
+         "provider" : "maven2",
+         "format" : "maven2",
+         "exposed" : true
+      }
+   ]
+}`
+
+var groups string = `
+{
+   "data" : [
+      {
+         "id" : "snapshotgroup",
+         "name" : "SnapshotGroup",
+         "format" : "maven2",
+         "exposed" : true,
+         "contentResourceURI" : "http://localhost:8081/nexus/content/groups/snapshotgroup",
+         "repositories" : [
+            {
+               "name" : "plat.trnk.trnk679",
+               "id" : "plat.trnk.trnk679",
+               "resourceURI" : "http://localhost:8081/nexus/service/local/repo_groups/snapshotgroup/plat.trnk.trnk679"
+            }
+         ]
+      },
+      {
+         "id" : "public",
+         "name" : "Public Repositories",
+         "format" : "maven2",
+         "exposed" : true,
+         "contentResourceURI" : "http://localhost:8081/nexus/content/groups/public",
+         "repositories" : []
+      }
+   ]
+}`
+
+func TestListRepositories(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("Wanted GET but got %s\n", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/service/local/repositories") {
+			t.Fatalf("Wanted URL suffix /service/local/repositories but got: %s\n", r.URL.Path)
+		}
+		if r.Header.Get("Accept") != "application/json" {
+			t.Fatalf("Wanted application/json but got %s for Accept header", r.Header.Get("Accept"))
+		}
+		fmt.Fprintf(w, "%s", repositories)
+	}))
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	repos, rc, err := client.ListRepositories(RepositoryFilter{})
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 200 {
+		t.Fatalf("Want 200 but got %d\n", rc)
+	}
+	if len(repos) != 4 {
+		t.Fatalf("Want 4 but got %d\n", len(repos))
+	}
+
+	repo := repos[1]
+	if repo.ID != "plat.trnk.trnk679" {
+		t.Fatalf("Want plat.trnk.trnk679 but got %v\n", repo.ID)
+	}
+	if repo.Type != HostedType {
+		t.Fatalf("Want hosted but got %v\n", repo.Type)
+	}
+	if repo.Policy != SnapshotPolicy {
+		t.Fatalf("Want SNAPSHOT but got %v\n", repo.Policy)
+	}
+	if repo.Format != "maven2" {
+		t.Fatalf("Want maven2 but got %v\n", repo.Format)
+	}
+	if repo.ContentResourceURI != "http://localhost:8081/nexus/content/repositories/plat.trnk.trnk679" {
+		t.Fatalf("Want http://localhost:8081/nexus/content/repositories/plat.trnk.trnk679 but got %v\n", repo.ContentResourceURI)
+	}
+}
+
+func TestListRepositoriesFiltered(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s", repositories)
+	}))
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+
+	var tests = []struct {
+		filter RepositoryFilter
+		want   []RepositoryID
+	}{
+		{RepositoryFilter{IDPrefix: "plat.trnk."}, []RepositoryID{"plat.trnk.trnk679", "plat.trnk.trnk680"}},
+		{RepositoryFilter{IDPattern: regexp.MustCompile(`^plat\.trnk\.trnk\d+$`), Policy: SnapshotPolicy}, []RepositoryID{"plat.trnk.trnk679", "plat.trnk.trnk680"}},
+		{RepositoryFilter{IDPattern: regexp.MustCompile(`679$`)}, []RepositoryID{"plat.trnk.trnk679"}},
+		{RepositoryFilter{Policy: ReleasePolicy}, []RepositoryID{"releases", "central"}},
+		{RepositoryFilter{Policy: ReleasePolicy, Type: ProxyType}, []RepositoryID{"central"}},
+		{RepositoryFilter{Type: VirtualType}, []RepositoryID{}},
+	}
+	for _, test := range tests {
+		repos, _, err := client.ListRepositories(test.filter)
+		if err != nil {
+			t.Fatalf("Expecting no error but got one: %v\n", err)
+		}
+		if len(repos) != len(test.want) {
+			t.Fatalf("Want %v but got %v\n", test.want, repos)
+		}
+		for i, id := range test.want {
+			if repos[i].ID != id {
+				t.Fatalf("Want %v but got %v\n", id, repos[i].ID)
+			}
+		}
+	}
+}
+
+func TestListRepositoryGroups(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/service/local/repo_groups") {
+			t.Fatalf("Wanted URL suffix /service/local/repo_groups but got: %s\n", r.URL.Path)
+		}
+		fmt.Fprintf(w, "%s", groups)
+	}))
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	all, _, err := client.ListRepositoryGroups(RepositoryFilter{})
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("Want 2 but got %d\n", len(all))
+	}
+
+	snapshots, _, err := client.ListRepositoryGroups(RepositoryFilter{IDPrefix: "snapshot"})
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if len(snapshots) != 1 {
+		t.Fatalf("Want 1 but got %d\n", len(snapshots))
+	}
+	group := snapshots[0]
+	if group.ContentResourceURI != "http://localhost:8081/nexus/content/groups/snapshotgroup" {
+		t.Fatalf("Want http://localhost:8081/nexus/content/groups/snapshotgroup but got %v\n", group.ContentResourceURI)
+	}
+	if len(group.Repositories) != 1 || group.Repositories[0].ID != "plat.trnk.trnk679" {
+		t.Fatalf("Want [plat.trnk.trnk679] but got %v\n", group.Repositories)
+	}
+}
@@ -0,0 +1,89 @@
+package maventools
+
+import (
+	"context"
+	"log/slog"
+)
+
+type (
+	// Logger receives the structured events a client emits: each HTTP request it makes, each retry, and each change it
+	// skips because the repository manager already reflects it.  Set ClientConfig.Logger to receive them; a nil Logger
+	// discards them.
+	Logger interface {
+		Log(ctx context.Context, level LogLevel, msg string, fields ...Field)
+	}
+
+	// LogLevel orders events by importance.  Its values match those of slog.Level.
+	LogLevel int
+
+	// Field is a key-value pair attached to an event.
+	Field struct {
+		Key   string
+		Value interface{}
+	}
+
+	nopLogger struct{}
+
+	slogLogger struct {
+		logger *slog.Logger
+	}
+)
+
+const (
+	LevelDebug LogLevel = -4
+	LevelInfo  LogLevel = 0
+	LevelWarn  LogLevel = 4
+	LevelError LogLevel = 8
+)
+
+// Keys of the fields attached to events.
+const (
+	FieldOp         = "op"
+	FieldMethod     = "method"
+	FieldURL        = "url"
+	FieldStatus     = "status"
+	FieldAttempt    = "attempt"
+	FieldDuration   = "duration"
+	FieldDelay      = "delay"
+	FieldError      = "error"
+	FieldRepository = "repository"
+	FieldGroup      = "group"
+	FieldMembers    = "members"
+	FieldWritten    = "written"
+)
+
+// NopLogger returns a Logger that discards every event.
+func NopLogger() Logger {
+	return nopLogger{}
+}
+
+func (nopLogger) Log(context.Context, LogLevel, string, ...Field) {}
+
+// NewSlogLogger returns a Logger that hands events to logger, with each field as an attribute.
+func NewSlogLogger(logger *slog.Logger) Logger {
+	return slogLogger{logger: logger}
+}
+
+func (l slogLogger) Log(ctx context.Context, level LogLevel, msg string, fields ...Field) {
+	if !l.logger.Enabled(ctx, slog.Level(level)) {
+		return
+	}
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, f := range fields {
+		attrs = append(attrs, slog.Any(f.Key, f.Value))
+	}
+	l.logger.LogAttrs(ctx, slog.Level(level), msg, attrs...)
+}
+
+// logger returns the configured Logger, or one that discards events if there is none.
+func (config ClientConfig) logger() Logger {
+	if config.Logger == nil {
+		return nopLogger{}
+	}
+	return config.Logger
+}
+
+// logAlreadyInGroup records that op left the group specified by groupID alone because repositoryID was already a member.
+func (config ClientConfig) logAlreadyInGroup(ctx context.Context, op string, repositoryID RepositoryID, groupID GroupID) {
+	config.logger().Log(ctx, LevelInfo, "already in repository group; not writing", Field{FieldOp, op}, Field{FieldRepository, repositoryID}, Field{FieldGroup, groupID})
+}
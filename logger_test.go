@@ -0,0 +1,132 @@
+package maventools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type (
+	event struct {
+		level  LogLevel
+		msg    string
+		fields map[string]interface{}
+	}
+
+	recordingLogger struct {
+		sync.Mutex
+		events []event
+	}
+)
+
+func (l *recordingLogger) Log(ctx context.Context, level LogLevel, msg string, fields ...Field) {
+	e := event{level: level, msg: msg, fields: make(map[string]interface{})}
+	for _, f := range fields {
+		e.fields[f.Key] = f.Value
+	}
+	l.Lock()
+	l.events = append(l.events, e)
+	l.Unlock()
+}
+
+func TestLoggerRequestAndRetry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(503)
+			return
+		}
+		w.WriteHeader(204)
+	}))
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	client := NewNexusClient(server.URL, "user", "password")
+	client.Logger = logger
+	client.RetryPolicy = NewRetryPolicy()
+	client.RetryPolicy.InitialBackoff = time.Millisecond
+	if _, err := client.DeleteRepository("somerepo"); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+
+	if len(logger.events) != 3 {
+		t.Fatalf("Want 3 events but got %d: %v\n", len(logger.events), logger.events)
+	}
+	want := []struct {
+		msg     string
+		level   LogLevel
+		status  int
+		attempt int
+	}{
+		{"request", LevelDebug, 503, 1},
+		{"retrying request", LevelWarn, 0, 1},
+		{"request", LevelDebug, 204, 2},
+	}
+	for i, w := range want {
+		e := logger.events[i]
+		if e.msg != w.msg || e.level != w.level {
+			t.Fatalf("Want %s at level %d but got %s at level %d\n", w.msg, w.level, e.msg, e.level)
+		}
+		if e.fields[FieldOp] != "NexusClient.DeleteRepository" {
+			t.Fatalf("Want NexusClient.DeleteRepository but got %v\n", e.fields[FieldOp])
+		}
+		if e.fields[FieldAttempt] != w.attempt {
+			t.Fatalf("Want attempt %d but got %v\n", w.attempt, e.fields[FieldAttempt])
+		}
+		if w.status != 0 && e.fields[FieldStatus] != w.status {
+			t.Fatalf("Want status %d but got %v\n", w.status, e.fields[FieldStatus])
+		}
+	}
+	if logger.events[2].fields[FieldURL] != server.URL+"/service/local/repositories/somerepo" {
+		t.Fatalf("Want %s but got %v\n", server.URL+"/service/local/repositories/somerepo", logger.events[2].fields[FieldURL])
+	}
+	if _, ok := logger.events[0].fields[FieldError]; !ok {
+		t.Fatalf("Want an error field on the failed request but there is none\n")
+	}
+}
+
+func TestLoggerAlreadyInGroup(t *testing.T) {
+	gs, server := newGroupServer(t, "a")
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	client := NewNexusClient(server.URL, "user", "password")
+	client.Logger = logger
+	if rc, err := client.AddRepositoryToGroup("a", "snapshotgroup"); err != nil || rc != 0 {
+		t.Fatalf("Want 0 but got %d, %v\n", rc, err)
+	}
+	if gs.puts != 0 {
+		t.Fatalf("Want no PUT but got %d\n", gs.puts)
+	}
+
+	e := logger.events[len(logger.events)-1]
+	if e.level != LevelInfo || e.fields[FieldRepository] != RepositoryID("a") || e.fields[FieldGroup] != GroupID("snapshotgroup") {
+		t.Fatalf("Want an info event for a in snapshotgroup but got %+v\n", e)
+	}
+}
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewSlogLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})))
+
+	logger.Log(context.Background(), LevelDebug, "request", Field{FieldOp, "NexusClient.DeleteRepository"})
+	if buf.Len() != 0 {
+		t.Fatalf("Want debug events filtered out but got %s\n", buf.String())
+	}
+
+	logger.Log(context.Background(), LevelWarn, "retrying request", Field{FieldOp, "NexusClient.DeleteRepository"}, Field{FieldGroup, GroupID("snapshotgroup")})
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if record["level"] != "WARN" || record["msg"] != "retrying request" || record[FieldOp] != "NexusClient.DeleteRepository" || record[FieldGroup] != "snapshotgroup" {
+		t.Fatalf("Want a WARN record with op and group but got %v\n", record)
+	}
+}
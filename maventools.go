@@ -1,42 +1,135 @@
 package maventools
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"io"
 	"net/http"
-	"os"
-)
 
-var Log *log.Logger = log.New(os.Stdout, "", log.Ldate|log.Ltime|log.Lshortfile)
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
 
 // Types expected to be common to Nexus and other repo managers
 type (
 
 	// ClientOps defines the service methods on a Client.  This interface should be suffiently expressive to capture Nexus and Artifactory behavior.
-	// Integer return values are the underlying HTTP response codes.
+	// Integer return values are the underlying HTTP response codes, and unexpected ones are reported as a *StatusError.
+	// Each method has a Context variant whose context bounds both the HTTP requests it makes and any retries still pending.
 	IClient interface {
 		RepositoryExists(RepositoryID) (bool, error)
 		CreateSnapshotRepository(RepositoryID) (int, error)
+		CreateReleaseRepository(RepositoryID) (int, error)
+		CreateHostedRepository(HostedRepositoryOptions) (int, error)
 		DeleteRepository(RepositoryID) (int, error)
 		AddRepositoryToGroup(RepositoryID, GroupID) (int, error)
 		RemoveRepositoryFromGroup(RepositoryID, GroupID) (int, error)
+		AddRepositoriesToGroup([]RepositoryID, GroupID) (map[RepositoryID]MembershipOutcome, int, error)
+		RemoveRepositoriesFromGroup([]RepositoryID, GroupID) (map[RepositoryID]MembershipOutcome, int, error)
+		ChangeGroupMembership(MembershipChange, GroupID) (map[RepositoryID]MembershipOutcome, int, error)
 		RepositoryGroup(GroupID) (RepositoryGroup, int, error)
+		CreateRepositoryGroup(RepositoryGroupOptions) (int, error)
+		DeleteRepositoryGroup(GroupID) (int, error)
+		ListRepositories(RepositoryFilter) ([]Repository, int, error)
+		ListRepositoryGroups(RepositoryFilter) ([]RepositoryGroup, int, error)
+		// UploadArtifact(repositoryID, coordinate, r) deploys the content read from r as the file of coordinate in the
+		// repository with the given repositoryID, PUTs its .md5, .sha1, .sha256 and .sha512 checksum files, and adds the
+		// version to the artifact's maven-metadata.xml.  A timestamped snapshot version, such as 1.0-20240102.030405-6, is
+		// recorded in the version's maven-metadata.xml as well.  The content is streamed; it is retried only if r is an
+		// io.Seeker.  When error is nil, the integer return value is the HTTP response code of the PUT of the content.
+		UploadArtifact(RepositoryID, Coordinate, io.Reader) (int, error)
+		// DownloadArtifact(contentResourceURI, coordinate, w) writes the file of coordinate in the repository or group
+		// whose content is at contentResourceURI, such as the ContentResourceURI of a Repository or RepositoryGroup, to
+		// w.  The content is verified against the .sha256 and .sha1 checksum files beside it, at least one of which must
+		// exist; a mismatch is reported as a *ChecksumError once the content has been written.  When error is nil, the
+		// integer return value is the HTTP response code of the GET of the content.
+		DownloadArtifact(string, Coordinate, io.Writer) (int, error)
+		// ResolveSnapshot(contentResourceURI, coordinate) returns coordinate with its snapshot version, such as
+		// 1.0-SNAPSHOT, replaced by the timestamped version of the file last deployed to the repository or group whose
+		// content is at contentResourceURI, so that the Path of the result names that file.  The version is read from the
+		// maven-metadata.xml of the snapshot version.  Other coordinates are returned as they are.  When error is nil, the
+		// integer return value is the HTTP response code of the GET of the metadata, or 0 if there was none.
+		ResolveSnapshot(string, Coordinate) (Coordinate, int, error)
+
+		RepositoryExistsContext(context.Context, RepositoryID) (bool, error)
+		CreateSnapshotRepositoryContext(context.Context, RepositoryID) (int, error)
+		CreateReleaseRepositoryContext(context.Context, RepositoryID) (int, error)
+		CreateHostedRepositoryContext(context.Context, HostedRepositoryOptions) (int, error)
+		DeleteRepositoryContext(context.Context, RepositoryID) (int, error)
+		AddRepositoryToGroupContext(context.Context, RepositoryID, GroupID) (int, error)
+		RemoveRepositoryFromGroupContext(context.Context, RepositoryID, GroupID) (int, error)
+		AddRepositoriesToGroupContext(context.Context, []RepositoryID, GroupID) (map[RepositoryID]MembershipOutcome, int, error)
+		RemoveRepositoriesFromGroupContext(context.Context, []RepositoryID, GroupID) (map[RepositoryID]MembershipOutcome, int, error)
+		ChangeGroupMembershipContext(context.Context, MembershipChange, GroupID) (map[RepositoryID]MembershipOutcome, int, error)
+		RepositoryGroupContext(context.Context, GroupID) (RepositoryGroup, int, error)
+		CreateRepositoryGroupContext(context.Context, RepositoryGroupOptions) (int, error)
+		DeleteRepositoryGroupContext(context.Context, GroupID) (int, error)
+		ListRepositoriesContext(context.Context, RepositoryFilter) ([]Repository, int, error)
+		ListRepositoryGroupsContext(context.Context, RepositoryFilter) ([]RepositoryGroup, int, error)
+		UploadArtifactContext(context.Context, RepositoryID, Coordinate, io.Reader) (int, error)
+		DownloadArtifactContext(context.Context, string, Coordinate, io.Writer) (int, error)
+		ResolveSnapshotContext(context.Context, string, Coordinate) (Coordinate, int, error)
 	}
 
 	RepositoryID string
 
 	GroupID string
 
+	// RepositoryPolicy determines which kinds of artifact versions a hosted repository accepts.
+	RepositoryPolicy string
+
+	// WritePolicy determines whether artifacts in a hosted repository can be deployed and redeployed.
+	WritePolicy string
+
+	// HostedRepositoryOptions configures a hosted repository created by CreateHostedRepository.  Start from
+	// NewHostedRepositoryOptions, which carries the settings CreateSnapshotRepository uses, and adjust from there.
+	HostedRepositoryOptions struct {
+		ID RepositoryID
+		// Display name.  Defaults to the ID.
+		Name   string
+		Policy RepositoryPolicy
+		// Defaults to AllowWrite.
+		WritePolicy WritePolicy
+		Browseable  bool
+		Indexable   bool
+		Exposed     bool
+		// Minutes for which a repository manager remembers that an artifact was not found.
+		NotFoundCacheTTL int
+	}
+
+	// RepositoryType distinguishes hosted repositories from those that proxy or aggregate others.
+	RepositoryType string
+
 	RepositoryGroup struct {
 		ID                 GroupID
 		Name               string
+		Format             string
 		ContentResourceURI string
 		Repositories       []Repository
 	}
 
+	// RepositoryGroupOptions configures a repository group created by CreateRepositoryGroup.
+	RepositoryGroupOptions struct {
+		ID GroupID
+		// Display name.  Defaults to the ID.
+		Name string
+		// Defaults to maven2.
+		Provider string
+		// Initial members, in resolution order.
+		Repositories []RepositoryID
+		Exposed      bool
+	}
+
+	// Repository describes a repository.  Members of a RepositoryGroup carry only the ID, Name and ResourceURI; the remaining
+	// fields are filled in by ListRepositories.
 	Repository struct {
-		Name        string
-		ID          RepositoryID
-		ResourceURI string
+		Name               string
+		ID                 RepositoryID
+		ResourceURI        string
+		Type               RepositoryType
+		Policy             RepositoryPolicy
+		Format             string
+		ContentResourceURI string
 	}
 
 	ClientConfig struct {
@@ -47,7 +140,91 @@ type (
 		// Admin username and password capable of updating the artifact repository
 		Username string
 		Password string
+		// Supplies the credentials of each request if not nil, in which case Username and Password are ignored.
+		Authenticator Authenticator
 		// Underlying network client
 		HttpClient *http.Client
+		// How requests that fail with a transient error are retried.  The zero value stands for NewRetryPolicy().
+		RetryPolicy RetryPolicy
+		// Receives the client's structured events.  Nil discards them.
+		Logger Logger
+		// Told about every HTTP exchange, if not nil.
+		Observer Observer
+		// Enables tracing if not nil: each operation records a span, with a child span for each HTTP request it makes.
+		TracerProvider trace.TracerProvider
+		// Carries the trace context to the server in the headers of each request.  Defaults to W3C Trace Context.
+		Propagator propagation.TextMapPropagator
 	}
 )
+
+const (
+	ReleasePolicy  RepositoryPolicy = "RELEASE"
+	SnapshotPolicy RepositoryPolicy = "SNAPSHOT"
+	MixedPolicy    RepositoryPolicy = "MIXED"
+
+	HostedType RepositoryType = "hosted"
+	ProxyType  RepositoryType = "proxy"
+	// A Nexus 2 virtual repository, which presents another repository in a different layout.
+	VirtualType RepositoryType = "virtual"
+
+	AllowWrite     WritePolicy = "ALLOW_WRITE"
+	AllowWriteOnce WritePolicy = "ALLOW_WRITE_ONCE"
+	ReadOnly       WritePolicy = "READ_ONLY"
+)
+
+// NewHostedRepositoryOptions returns options for a browseable, indexable and exposed repository named after repositoryID that
+// accepts artifacts of the given policy and allows redeployment.
+func NewHostedRepositoryOptions(repositoryID RepositoryID, policy RepositoryPolicy) HostedRepositoryOptions {
+	return HostedRepositoryOptions{
+		ID:               repositoryID,
+		Name:             string(repositoryID),
+		Policy:           policy,
+		WritePolicy:      AllowWrite,
+		Browseable:       true,
+		Indexable:        true,
+		Exposed:          true,
+		NotFoundCacheTTL: 1440,
+	}
+}
+
+// NewRepositoryGroupOptions returns options for an exposed maven2 group named after groupID whose members are repositories, in order.
+func NewRepositoryGroupOptions(groupID GroupID, repositories ...RepositoryID) RepositoryGroupOptions {
+	return RepositoryGroupOptions{ID: groupID, Name: string(groupID), Provider: "maven2", Repositories: repositories, Exposed: true}
+}
+
+// withDefaults fills in the optional fields of options and rejects options that cannot describe a group.
+func (options RepositoryGroupOptions) withDefaults() (RepositoryGroupOptions, error) {
+	if options.ID == "" {
+		return options, fmt.Errorf("RepositoryGroupOptions: group ID is required")
+	}
+	if options.Name == "" {
+		options.Name = string(options.ID)
+	}
+	if options.Provider == "" {
+		options.Provider = "maven2"
+	}
+	return options, nil
+}
+
+// withDefaults fills in the optional fields of options and rejects options that cannot describe a repository.
+func (options HostedRepositoryOptions) withDefaults() (HostedRepositoryOptions, error) {
+	if options.ID == "" {
+		return options, fmt.Errorf("HostedRepositoryOptions: repository ID is required")
+	}
+	switch options.Policy {
+	case ReleasePolicy, SnapshotPolicy, MixedPolicy:
+	default:
+		return options, fmt.Errorf("HostedRepositoryOptions: unknown repository policy %q", options.Policy)
+	}
+	if options.Name == "" {
+		options.Name = string(options.ID)
+	}
+	switch options.WritePolicy {
+	case "":
+		options.WritePolicy = AllowWrite
+	case AllowWrite, AllowWriteOnce, ReadOnly:
+	default:
+		return options, fmt.Errorf("HostedRepositoryOptions: unknown write policy %q", options.WritePolicy)
+	}
+	return options, nil
+}
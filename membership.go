@@ -0,0 +1,129 @@
+package maventools
+
+import "fmt"
+
+type (
+	// MembershipChange describes repositories to add to and remove from a repository group in one update.  Repositories in
+	// Add that are not yet members are appended in the order given.
+	MembershipChange struct {
+		Add    []RepositoryID
+		Remove []RepositoryID
+	}
+
+	// MembershipOutcome says what ChangeGroupMembership found or did for one repository.
+	MembershipOutcome string
+)
+
+const (
+	// MemberAdded means the repository was added to the group.
+	MemberAdded MembershipOutcome = "added"
+	// MemberAlreadyPresent means the repository was to be added but was already a member.
+	MemberAlreadyPresent MembershipOutcome = "already present"
+	// MemberNotFound means the repository was to be added but does not exist on the server, so it was left out of the update.
+	MemberNotFound MembershipOutcome = "not found"
+	// MemberRemoved means the repository was removed from the group.
+	MemberRemoved MembershipOutcome = "removed"
+	// MemberNotAMember means the repository was to be removed but was not a member of the group.  It says nothing about
+	// whether the repository exists.
+	MemberNotAMember MembershipOutcome = "not a member"
+)
+
+// validate reports whether c names an empty repository ID or both adds and removes the same repository.
+func (c MembershipChange) validate() error {
+	add := make(map[RepositoryID]bool)
+	for _, id := range c.Add {
+		if id == "" {
+			return fmt.Errorf("MembershipChange: empty repository ID")
+		}
+		add[id] = true
+	}
+	for _, id := range c.Remove {
+		if id == "" {
+			return fmt.Errorf("MembershipChange: empty repository ID")
+		}
+		if add[id] {
+			return fmt.Errorf("MembershipChange: repository %v is both added and removed", id)
+		}
+	}
+	return nil
+}
+
+// missingFrom returns the repositories in ids that are not in existing.
+func missingFrom(ids []RepositoryID, existing []RepositoryID) []RepositoryID {
+	known := make(map[RepositoryID]bool)
+	for _, id := range existing {
+		known[id] = true
+	}
+	var missing []RepositoryID
+	for _, id := range ids {
+		if !known[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// apply returns a change to a member list that carries out c, and the outcomes for the repositories it names.  Repositories
+// in missing are not added and have the outcome MemberNotFound.  The other outcomes are filled in when the change is first
+// applied, so they describe the group as it was before the update.
+func (c MembershipChange) apply(missing []RepositoryID) (func([]RepositoryID) ([]RepositoryID, error), map[RepositoryID]MembershipOutcome, error) {
+	if err := c.validate(); err != nil {
+		return nil, nil, err
+	}
+	notFound := make(map[RepositoryID]bool)
+	for _, id := range missing {
+		notFound[id] = true
+	}
+	add := make(map[RepositoryID]bool)
+	for _, id := range c.Add {
+		if !notFound[id] {
+			add[id] = true
+		}
+	}
+	remove := make(map[RepositoryID]bool)
+	for _, id := range c.Remove {
+		remove[id] = true
+	}
+
+	outcomes := make(map[RepositoryID]MembershipOutcome)
+	for id := range notFound {
+		outcomes[id] = MemberNotFound
+	}
+	applied := false
+	change := func(members []RepositoryID) ([]RepositoryID, error) {
+		present := make(map[RepositoryID]bool)
+		result := make([]RepositoryID, 0, len(members)+len(c.Add))
+		for _, id := range members {
+			present[id] = true
+			if !remove[id] {
+				result = append(result, id)
+			}
+		}
+		for _, id := range c.Add {
+			if add[id] && !present[id] {
+				present[id] = true
+				result = append(result, id)
+			}
+		}
+
+		if !applied {
+			applied = true
+			for id := range add {
+				outcomes[id] = MemberAdded
+			}
+			for id := range remove {
+				outcomes[id] = MemberNotAMember
+			}
+			for _, id := range members {
+				if add[id] {
+					outcomes[id] = MemberAlreadyPresent
+				}
+				if remove[id] {
+					outcomes[id] = MemberRemoved
+				}
+			}
+		}
+		return result, nil
+	}
+	return change, outcomes, nil
+}
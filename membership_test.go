@@ -0,0 +1,132 @@
+package maventools
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestAddRepositoriesToGroup(t *testing.T) {
+	gs, server := newGroupServer(t, "a", "b")
+	defer server.Close()
+	gs.repositories = append(gs.repositories, "c", "d")
+
+	client := NewNexusClient(server.URL, "user", "password")
+	outcomes, rc, err := client.AddRepositoriesToGroup([]RepositoryID{"c", "a", "d", "c"}, "snapshotgroup")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 200 {
+		t.Fatalf("Want 200 but got %d\n", rc)
+	}
+	if gs.puts != 1 {
+		t.Fatalf("Want 1 PUT but got %d\n", gs.puts)
+	}
+	if got := fmt.Sprint(gs.members()); got != "[a b c d]" {
+		t.Fatalf("Want [a b c d] but got %s\n", got)
+	}
+	want := map[RepositoryID]MembershipOutcome{"a": MemberAlreadyPresent, "c": MemberAdded, "d": MemberAdded}
+	if !reflect.DeepEqual(outcomes, want) {
+		t.Fatalf("Want %v but got %v\n", want, outcomes)
+	}
+
+	outcomes, rc, err = client.AddRepositoriesToGroup([]RepositoryID{"a", "d"}, "snapshotgroup")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 0 || gs.puts != 1 {
+		t.Fatalf("Want no PUT but got %d (rc %d)\n", gs.puts-1, rc)
+	}
+	if outcomes["a"] != MemberAlreadyPresent || outcomes["d"] != MemberAlreadyPresent {
+		t.Fatalf("Want both already present but got %v\n", outcomes)
+	}
+}
+
+func TestRemoveRepositoriesFromGroup(t *testing.T) {
+	gs, server := newGroupServer(t, "a", "b", "c")
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	outcomes, _, err := client.RemoveRepositoriesFromGroup([]RepositoryID{"c", "x", "a"}, "snapshotgroup")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if gs.puts != 1 {
+		t.Fatalf("Want 1 PUT but got %d\n", gs.puts)
+	}
+	if got := fmt.Sprint(gs.members()); got != "[b]" {
+		t.Fatalf("Want [b] but got %s\n", got)
+	}
+	want := map[RepositoryID]MembershipOutcome{"a": MemberRemoved, "c": MemberRemoved, "x": MemberNotAMember}
+	if !reflect.DeepEqual(outcomes, want) {
+		t.Fatalf("Want %v but got %v\n", want, outcomes)
+	}
+}
+
+func TestChangeGroupMembership(t *testing.T) {
+	gs, server := newGroupServer(t, "a", "b")
+	defer server.Close()
+	gs.repositories = append(gs.repositories, "c")
+
+	client := NewNexusClient(server.URL, "user", "password")
+	outcomes, _, err := client.ChangeGroupMembership(MembershipChange{Add: []RepositoryID{"c", "b"}, Remove: []RepositoryID{"a", "x"}}, "snapshotgroup")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if gs.puts != 1 {
+		t.Fatalf("Want 1 PUT but got %d\n", gs.puts)
+	}
+	if got := fmt.Sprint(gs.members()); got != "[b c]" {
+		t.Fatalf("Want [b c] but got %s\n", got)
+	}
+	want := map[RepositoryID]MembershipOutcome{"a": MemberRemoved, "b": MemberAlreadyPresent, "c": MemberAdded, "x": MemberNotAMember}
+	if !reflect.DeepEqual(outcomes, want) {
+		t.Fatalf("Want %v but got %v\n", want, outcomes)
+	}
+}
+
+func TestAddMissingRepositoriesToGroup(t *testing.T) {
+	gs, server := newGroupServer(t, "a")
+	defer server.Close()
+	gs.repositories = append(gs.repositories, "c")
+	gs.groups = []GroupID{"othergroup"}
+
+	client := NewNexusClient(server.URL, "user", "password")
+	outcomes, rc, err := client.AddRepositoriesToGroup([]RepositoryID{"c", "othergroup", "nosuchrepo"}, "snapshotgroup")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 200 {
+		t.Fatalf("Want 200 but got %d\n", rc)
+	}
+	if got := fmt.Sprint(gs.members()); got != "[a c othergroup]" {
+		t.Fatalf("Want [a c othergroup] but got %s\n", got)
+	}
+	want := map[RepositoryID]MembershipOutcome{"c": MemberAdded, "othergroup": MemberAdded, "nosuchrepo": MemberNotFound}
+	if !reflect.DeepEqual(outcomes, want) {
+		t.Fatalf("Want %v but got %v\n", want, outcomes)
+	}
+
+	outcomes, rc, err = client.AddRepositoriesToGroup([]RepositoryID{"nosuchrepo"}, "snapshotgroup")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 0 || gs.puts != 1 {
+		t.Fatalf("Want no PUT but got %d (rc %d)\n", gs.puts-1, rc)
+	}
+	if outcomes["nosuchrepo"] != MemberNotFound {
+		t.Fatalf("Want %v but got %v\n", MemberNotFound, outcomes["nosuchrepo"])
+	}
+}
+
+func TestChangeGroupMembershipInvalid(t *testing.T) {
+	client := NewNexusClient("http://localhost:0", "user", "password")
+	for _, change := range []MembershipChange{
+		{Add: []RepositoryID{"a"}, Remove: []RepositoryID{"a"}},
+		{Add: []RepositoryID{""}},
+	} {
+		if _, _, err := client.ChangeGroupMembership(change, "snapshotgroup"); err == nil {
+			t.Fatalf("Expecting an error but did not get one for %+v\n", change)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package maventools
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// Metadata is the content of a maven-metadata.xml file.  The file in the directory of an artifact lists the artifact's
+	// versions; the file in the directory of a snapshot version says which timestamped files were deployed last.
+	Metadata struct {
+		XMLName xml.Name `xml:"metadata"`
+		// 1.1.0 for metadata that lists SnapshotVersions.
+		ModelVersion string `xml:"modelVersion,attr,omitempty"`
+		GroupID      string `xml:"groupId,omitempty"`
+		ArtifactID   string `xml:"artifactId,omitempty"`
+		// The snapshot version, such as 1.0-SNAPSHOT, of version-level metadata.
+		Version    string      `xml:"version,omitempty"`
+		Versioning *Versioning `xml:"versioning,omitempty"`
+	}
+
+	Versioning struct {
+		// The most recently deployed version, snapshots included.
+		Latest string `xml:"latest,omitempty"`
+		// The most recently deployed version that is not a snapshot.
+		Release string `xml:"release,omitempty"`
+		// The last deployment of a snapshot version, in version-level metadata.
+		Snapshot *Snapshot `xml:"snapshot,omitempty"`
+		Versions []string  `xml:"versions>version,omitempty"`
+		// When the metadata last changed, in the form yyyyMMddHHmmss, UTC.
+		LastUpdated string `xml:"lastUpdated,omitempty"`
+		// The timestamped version of each file of a snapshot version, in version-level metadata.
+		SnapshotVersions []SnapshotVersion `xml:"snapshotVersions>snapshotVersion,omitempty"`
+	}
+
+	Snapshot struct {
+		// When the snapshot was deployed, in the form yyyyMMdd.HHmmss, UTC.
+		Timestamp   string `xml:"timestamp,omitempty"`
+		BuildNumber int    `xml:"buildNumber,omitempty"`
+		// Set in a local repository whose files keep the SNAPSHOT version in their names.
+		LocalCopy bool `xml:"localCopy,omitempty"`
+	}
+
+	// SnapshotVersion gives the version in the name of one file of a snapshot version.
+	SnapshotVersion struct {
+		Classifier string `xml:"classifier,omitempty"`
+		Extension  string `xml:"extension"`
+		// The timestamped version, for example 1.0-20240102.030405-6.
+		Value string `xml:"value"`
+		// When the file was deployed, in the form yyyyMMddHHmmss, UTC.
+		Updated string `xml:"updated,omitempty"`
+	}
+)
+
+const (
+	// metadataTimeFormat is the layout of Versioning.LastUpdated and SnapshotVersion.Updated.
+	metadataTimeFormat = "20060102150405"
+	// snapshotTimeFormat is the layout of Snapshot.Timestamp.
+	snapshotTimeFormat = "20060102.150405"
+)
+
+// versioningXML is the form in which Versioning is written.  Its lists are wrapped in pointers so that empty ones are left
+// out, which encoding/xml does not do for a versions>version tag.
+type (
+	versioningXML struct {
+		Latest           string               `xml:"latest,omitempty"`
+		Release          string               `xml:"release,omitempty"`
+		Snapshot         *Snapshot            `xml:"snapshot,omitempty"`
+		Versions         *versionList         `xml:"versions"`
+		LastUpdated      string               `xml:"lastUpdated,omitempty"`
+		SnapshotVersions *snapshotVersionList `xml:"snapshotVersions"`
+	}
+
+	versionList struct {
+		Version []string `xml:"version"`
+	}
+
+	snapshotVersionList struct {
+		SnapshotVersion []SnapshotVersion `xml:"snapshotVersion"`
+	}
+)
+
+// MarshalXML writes the versioning as Maven does, without empty lists.
+func (v Versioning) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	out := versioningXML{Latest: v.Latest, Release: v.Release, Snapshot: v.Snapshot, LastUpdated: v.LastUpdated}
+	if len(v.Versions) > 0 {
+		out.Versions = &versionList{v.Versions}
+	}
+	if len(v.SnapshotVersions) > 0 {
+		out.SnapshotVersions = &snapshotVersionList{v.SnapshotVersions}
+	}
+	return e.EncodeElement(out, start)
+}
+
+// ParseMetadata parses the content of a maven-metadata.xml file.
+func ParseMetadata(data []byte) (Metadata, error) {
+	var metadata Metadata
+	if err := xml.Unmarshal(data, &metadata); err != nil {
+		return Metadata{}, err
+	}
+	return metadata, nil
+}
+
+// Marshal returns the metadata as the content of a maven-metadata.xml file.
+func (metadata Metadata) Marshal() ([]byte, error) {
+	return marshalDocument(metadata)
+}
+
+// AddVersion records that version was deployed at the given time: it is added to the versions if missing and becomes the
+// latest version and, unless it is a snapshot, the release.
+func (metadata *Metadata) AddVersion(version string, at time.Time) {
+	if metadata.Versioning == nil {
+		metadata.Versioning = &Versioning{}
+	}
+	v := metadata.Versioning
+	found := false
+	for _, existing := range v.Versions {
+		if existing == version {
+			found = true
+			break
+		}
+	}
+	if !found {
+		v.Versions = append(v.Versions, version)
+	}
+	v.Latest = version
+	if !(Coordinate{Version: version}).IsSnapshot() {
+		v.Release = version
+	}
+	v.LastUpdated = at.UTC().Format(metadataTimeFormat)
+}
+
+// AddSnapshot records the deployment of the file of c, whose version is timestamped, such as 1.0-20240102.030405-6, in
+// version-level metadata.  The snapshot and the file's entry in SnapshotVersions are updated, or the entry is added.
+func (metadata *Metadata) AddSnapshot(c Coordinate, at time.Time) error {
+	m := timestampedVersion.FindStringSubmatch(c.Version)
+	if m == nil {
+		return fmt.Errorf("Metadata.AddSnapshot(): %s is not a timestamped snapshot version", c.Version)
+	}
+	if _, err := time.Parse(snapshotTimeFormat, m[2]); err != nil {
+		return fmt.Errorf("Metadata.AddSnapshot(): %s has an invalid timestamp: %v", c.Version, err)
+	}
+	buildNumber, err := strconv.Atoi(m[3])
+	if err != nil {
+		return fmt.Errorf("Metadata.AddSnapshot(): %s has an invalid build number: %v", c.Version, err)
+	}
+
+	metadata.ModelVersion = "1.1.0"
+	metadata.GroupID, metadata.ArtifactID, metadata.Version = c.GroupID, c.ArtifactID, c.BaseVersion()
+	if metadata.Versioning == nil {
+		metadata.Versioning = &Versioning{}
+	}
+	v := metadata.Versioning
+	updated := at.UTC().Format(metadataTimeFormat)
+	v.Snapshot = &Snapshot{Timestamp: m[2], BuildNumber: buildNumber}
+	v.LastUpdated = updated
+
+	entry := SnapshotVersion{Classifier: c.Classifier, Extension: c.extension(), Value: c.Version, Updated: updated}
+	for i, existing := range v.SnapshotVersions {
+		if existing.Classifier == entry.Classifier && existing.Extension == entry.Extension {
+			v.SnapshotVersions[i] = entry
+			return nil
+		}
+	}
+	v.SnapshotVersions = append(v.SnapshotVersions, entry)
+	return nil
+}
+
+// SnapshotVersion returns the timestamped version of the file of c, a snapshot such as 1.0-SNAPSHOT, according to the
+// version-level metadata.  SnapshotVersions are preferred; metadata that lacks them, as Maven 2 wrote it, gives the version
+// through its snapshot's timestamp and build number.  A local copy's files keep the version of c.  The boolean return value is
+// false if the metadata does not describe the file.
+func (metadata Metadata) SnapshotVersion(c Coordinate) (string, bool) {
+	v := metadata.Versioning
+	if v == nil || !strings.HasSuffix(c.Version, snapshotSuffix) {
+		return "", false
+	}
+	for _, entry := range v.SnapshotVersions {
+		if entry.Classifier == c.Classifier && entry.Extension == c.extension() {
+			return entry.Value, true
+		}
+	}
+	switch {
+	case v.Snapshot == nil:
+		return "", false
+	case v.Snapshot.LocalCopy:
+		return c.Version, true
+	case v.Snapshot.Timestamp == "" || v.Snapshot.BuildNumber == 0:
+		return "", false
+	}
+	base := strings.TrimSuffix(c.Version, snapshotSuffix)
+	return fmt.Sprintf("%s-%s-%d", base, v.Snapshot.Timestamp, v.Snapshot.BuildNumber), true
+}
+
+// HighestVersion returns the highest of the versions the metadata lists that satisfies r, in Maven order, leaving out
+// snapshots unless snapshots is set.  The boolean return value is false if there is none.
+func (metadata Metadata) HighestVersion(r VersionRange, snapshots bool) (string, bool) {
+	if metadata.Versioning == nil {
+		return "", false
+	}
+	var highest *Version
+	for _, s := range metadata.Versioning.Versions {
+		v := ParseVersion(s)
+		if (!snapshots && v.IsSnapshot()) || !r.Contains(v) {
+			continue
+		}
+		if highest == nil || v.Compare(*highest) > 0 {
+			highest = &v
+		}
+	}
+	if highest == nil {
+		return "", false
+	}
+	return highest.String(), true
+}
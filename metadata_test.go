@@ -0,0 +1,170 @@
+package maventools
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// snapshotMetadata is version-level metadata as Maven 3 deploys it.
+const snapshotMetadata = `<?xml version="1.0" encoding="UTF-8"?>
+<metadata modelVersion="1.1.0">
+  <groupId>org.example</groupId>
+  <artifactId>lib</artifactId>
+  <version>1.2-SNAPSHOT</version>
+  <versioning>
+    <snapshot>
+      <timestamp>20240102.030405</timestamp>
+      <buildNumber>7</buildNumber>
+    </snapshot>
+    <lastUpdated>20240102030405</lastUpdated>
+    <snapshotVersions>
+      <snapshotVersion>
+        <extension>jar</extension>
+        <value>1.2-20240102.030405-7</value>
+        <updated>20240102030405</updated>
+      </snapshotVersion>
+      <snapshotVersion>
+        <classifier>sources</classifier>
+        <extension>jar</extension>
+        <value>1.2-20240101.120000-6</value>
+        <updated>20240101120000</updated>
+      </snapshotVersion>
+      <snapshotVersion>
+        <extension>pom</extension>
+        <value>1.2-20240102.030405-7</value>
+        <updated>20240102030405</updated>
+      </snapshotVersion>
+    </snapshotVersions>
+  </versioning>
+</metadata>
+`
+
+func TestParseMetadata(t *testing.T) {
+	m, err := ParseMetadata([]byte(snapshotMetadata))
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if m.ModelVersion != "1.1.0" || m.Version != "1.2-SNAPSHOT" || m.Versioning == nil || m.Versioning.Snapshot == nil {
+		t.Fatalf("Want version-level metadata of 1.2-SNAPSHOT but got %+v\n", m)
+	}
+	if m.Versioning.Snapshot.BuildNumber != 7 || len(m.Versioning.SnapshotVersions) != 3 {
+		t.Fatalf("Want build 7 of 3 files but got %+v\n", *m.Versioning)
+	}
+
+	data, err := m.Marshal()
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if string(data) != snapshotMetadata {
+		t.Fatalf("Want %s but got %s\n", snapshotMetadata, data)
+	}
+}
+
+func TestMetadataSnapshotVersion(t *testing.T) {
+	m, _ := ParseMetadata([]byte(snapshotMetadata))
+	var tests = []struct {
+		c    Coordinate
+		want string
+	}{
+		{Coordinate{GroupID: "org.example", ArtifactID: "lib", Version: "1.2-SNAPSHOT"}, "1.2-20240102.030405-7"},
+		{Coordinate{GroupID: "org.example", ArtifactID: "lib", Version: "1.2-SNAPSHOT", Classifier: "sources"}, "1.2-20240101.120000-6"},
+		{Coordinate{GroupID: "org.example", ArtifactID: "lib", Version: "1.2-SNAPSHOT", Extension: "pom"}, "1.2-20240102.030405-7"},
+		// Without an entry of its own, a file takes the version of the last snapshot.
+		{Coordinate{GroupID: "org.example", ArtifactID: "lib", Version: "1.2-SNAPSHOT", Classifier: "javadoc"}, "1.2-20240102.030405-7"},
+	}
+	for _, test := range tests {
+		got, ok := m.SnapshotVersion(test.c)
+		if !ok || got != test.want {
+			t.Fatalf("Want %s for %s but got %s\n", test.want, test.c, got)
+		}
+	}
+
+	m.Versioning.SnapshotVersions = nil
+	m.Versioning.Snapshot = &Snapshot{LocalCopy: true}
+	if got, ok := m.SnapshotVersion(tests[0].c); !ok || got != "1.2-SNAPSHOT" {
+		t.Fatalf("Want 1.2-SNAPSHOT but got %s\n", got)
+	}
+	m.Versioning.Snapshot = nil
+	if _, ok := m.SnapshotVersion(tests[0].c); ok {
+		t.Fatalf("Want no version but got one\n")
+	}
+	if _, ok := m.SnapshotVersion(Coordinate{GroupID: "org.example", ArtifactID: "lib", Version: "1.2"}); ok {
+		t.Fatalf("Want no version of a release but got one\n")
+	}
+}
+
+func TestMetadataAddSnapshot(t *testing.T) {
+	var m Metadata
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, c := range []Coordinate{
+		{GroupID: "org.example", ArtifactID: "lib", Version: "1.2-20240101.120000-6"},
+		{GroupID: "org.example", ArtifactID: "lib", Version: "1.2-20240101.120000-6", Classifier: "sources"},
+		{GroupID: "org.example", ArtifactID: "lib", Version: "1.2-20240102.030405-7"},
+	} {
+		if err := m.AddSnapshot(c, at); err != nil {
+			t.Fatalf("Expecting no error but got one: %v\n", err)
+		}
+	}
+	if m.Version != "1.2-SNAPSHOT" || *m.Versioning.Snapshot != (Snapshot{Timestamp: "20240102.030405", BuildNumber: 7}) {
+		t.Fatalf("Want build 7 of 1.2-SNAPSHOT but got %+v\n", m)
+	}
+	if len(m.Versioning.SnapshotVersions) != 2 || m.Versioning.SnapshotVersions[0].Value != "1.2-20240102.030405-7" || m.Versioning.LastUpdated != "20240102030405" {
+		t.Fatalf("Want the jar replaced but got %+v\n", m.Versioning.SnapshotVersions)
+	}
+
+	for _, version := range []string{"1.2-SNAPSHOT", "1.2", "1.2-20241399.030405-7"} {
+		if err := m.AddSnapshot(Coordinate{GroupID: "org.example", ArtifactID: "lib", Version: version}, at); err == nil {
+			t.Fatalf("Expecting an error for %s but did not get one\n", version)
+		}
+	}
+}
+
+func TestResolveSnapshot(t *testing.T) {
+	server := newRecordingServer()
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	for _, version := range []string{"1.2-20240101.120000-6", "1.2-20240102.030405-7"} {
+		c := Coordinate{GroupID: "org.example", ArtifactID: "lib", Version: version}
+		if _, err := client.UploadArtifact("snapshots", c, strings.NewReader(version)); err != nil {
+			t.Fatalf("Expecting no error but got one: %v\n", err)
+		}
+	}
+
+	contentURI := server.URL + "/content/repositories/snapshots"
+	c, rc, err := client.ResolveSnapshot(contentURI, Coordinate{GroupID: "org.example", ArtifactID: "lib", Version: "1.2-SNAPSHOT"})
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 200 {
+		t.Fatalf("Want 200 but got %d\n", rc)
+	}
+	if c.Path() != "org/example/lib/1.2-SNAPSHOT/lib-1.2-20240102.030405-7.jar" {
+		t.Fatalf("Want the file of build 7 but got %s\n", c.Path())
+	}
+	var buf bytes.Buffer
+	if _, err := client.DownloadArtifact(contentURI, c, &buf); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if buf.String() != "1.2-20240102.030405-7" {
+		t.Fatalf("Want 1.2-20240102.030405-7 but got %s\n", buf.String())
+	}
+
+	artifact, err := ParseMetadata(server.files["/content/repositories/snapshots/org/example/lib/maven-metadata.xml"])
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if strings.Join(artifact.Versioning.Versions, ",") != "1.2-SNAPSHOT" || artifact.Versioning.Release != "" {
+		t.Fatalf("Want only 1.2-SNAPSHOT unreleased but got %+v\n", *artifact.Versioning)
+	}
+
+	release := Coordinate{GroupID: "org.example", ArtifactID: "lib", Version: "1.2"}
+	if c, rc, err := client.ResolveSnapshot(contentURI, release); err != nil || rc != 0 || c != release {
+		t.Fatalf("Want %s unchanged but got %s, %d, %v\n", release, c, rc, err)
+	}
+	if _, _, err := client.ResolveSnapshot(contentURI, Coordinate{GroupID: "org.example", ArtifactID: "other", Version: "1.0-SNAPSHOT"}); err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+}
@@ -1,14 +1,11 @@
 package maventools
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"encoding/xml"
-	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
-
-	"github.com/ae6rt/retry"
 )
 
 type (
@@ -57,8 +54,38 @@ type (
 		ResourceURI string       `json:"resourceURI"`
 	}
 
+	// The type retrieved to list repositories.
+	repoList struct {
+		Data []repositoryListItem `json:"data"`
+	}
+
+	repositoryListItem struct {
+		ID                 RepositoryID `json:"id"`
+		Name               string       `json:"name"`
+		ResourceURI        string       `json:"resourceURI"`
+		ContentResourceURI string       `json:"contentResourceURI"`
+		RepoType           string       `json:"repoType"`
+		RepoPolicy         string       `json:"repoPolicy"`
+		Format             string       `json:"format"`
+	}
+
+	// The type retrieved to list repository groups.
+	repoGroupList struct {
+		Data []RepositoryGroupData `json:"data"`
+	}
+
 	NexusClient struct {
 		ClientConfig
+
+		// GroupLockDir, when not empty, names a directory in which changes to the members of a repository group hold an
+		// exclusive lock on a file named after the group.  Processes that share the directory, such as builds on one agent,
+		// then edit any one group in turn.
+		GroupLockDir string
+
+		// groups coalesces concurrent membership changes made through copies of the same client.  A batch is written with
+		// the configuration of the copy that runs it.  Clients not made by NewNexusClient or NewClient, such as struct
+		// literals, have none and write each change on its own.
+		groups *groupBatcher
 	}
 )
 
@@ -66,55 +93,95 @@ type (
 // the form http://host:port/nexus.  username and password are the credentials of an admin user capable of creating and mutating data
 // within Nexus.
 func NewNexusClient(baseURL, username, password string) NexusClient {
-	return NexusClient{ClientConfig{BaseURL: baseURL, Username: username, Password: password, HttpClient: &http.Client{}}}
+	return NexusClient{ClientConfig: ClientConfig{BaseURL: baseURL, Username: username, Password: password, HttpClient: &http.Client{}}, groups: newGroupBatcher()}
 }
 
 // RepositoryExists checks whether a given repository specified by repositoryID exists.
+//
+// RepositoryExists uses context.Background internally; to specify the context, use RepositoryExistsContext.
 func (client NexusClient) RepositoryExists(repositoryID RepositoryID) (bool, error) {
-	retry := retry.New(3, retry.DefaultBackoffFunc)
+	return client.RepositoryExistsContext(context.Background(), repositoryID)
+}
 
-	var responseCode int
-	work := func() error {
-		req, err := http.NewRequest("HEAD", client.BaseURL+"/service/local/repositories/"+string(repositoryID), nil)
-		if err != nil {
-			return err
-		}
-		req.SetBasicAuth(client.Username, client.Password)
-		req.Header.Add("Accept", "application/json")
+// RepositoryExistsContext is like RepositoryExists but uses ctx for its HTTP requests and any pending retries.
+func (client NexusClient) RepositoryExistsContext(ctx context.Context, repositoryID RepositoryID) (_ bool, err error) {
+	ctx, span := client.startOperation(ctx, "NexusClient.RepositoryExists", repositoryAttribute(repositoryID))
+	defer func() { span.end(err) }()
 
-		resp, err := client.HttpClient.Do(req)
-		if err != nil {
-			return err
-		}
-		resp.Body.Close()
-
-		responseCode = resp.StatusCode
-		if !(responseCode == http.StatusOK || responseCode == http.StatusNotFound) {
-			return fmt.Errorf("HTTP HEAD status code for whether repository ID exists returns neither 200 or 404: %d\n", responseCode)
-		}
-		return nil
-	}
-	return responseCode == http.StatusOK, retry.Try(work)
+	rc, _, err := client.send(ctx, request{op: "NexusClient.RepositoryExists", method: "HEAD", path: "/service/local/repositories/" + string(repositoryID), ok: []int{http.StatusOK, http.StatusNotFound}})
+	return rc == http.StatusOK, err
 }
 
 // CreateSnapshotRepository creates a new hosted Maven2 SNAPSHOT repository with the given repositoryID.  The repository name
 // will be the same as the repositoryID.  When error is nil, the integer return value is the underlying HTTP response code.
+//
+// CreateSnapshotRepository uses context.Background internally; to specify the context, use CreateSnapshotRepositoryContext.
 func (client NexusClient) CreateSnapshotRepository(repositoryID RepositoryID) (int, error) {
+	return client.CreateSnapshotRepositoryContext(context.Background(), repositoryID)
+}
+
+// CreateSnapshotRepositoryContext is like CreateSnapshotRepository but uses ctx for its HTTP requests and any pending retries.
+func (client NexusClient) CreateSnapshotRepositoryContext(ctx context.Context, repositoryID RepositoryID) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "NexusClient.CreateSnapshotRepository", repositoryAttribute(repositoryID))
+	defer func() { span.end(err) }()
+
+	return client.createHostedRepository(ctx, "NexusClient.CreateSnapshotRepository", NewHostedRepositoryOptions(repositoryID, SnapshotPolicy))
+}
+
+// CreateReleaseRepository creates a new hosted Maven2 RELEASE repository with the given repositoryID.  The repository name
+// will be the same as the repositoryID.  When error is nil, the integer return value is the underlying HTTP response code.
+//
+// CreateReleaseRepository uses context.Background internally; to specify the context, use CreateReleaseRepositoryContext.
+func (client NexusClient) CreateReleaseRepository(repositoryID RepositoryID) (int, error) {
+	return client.CreateReleaseRepositoryContext(context.Background(), repositoryID)
+}
+
+// CreateReleaseRepositoryContext is like CreateReleaseRepository but uses ctx for its HTTP requests and any pending retries.
+func (client NexusClient) CreateReleaseRepositoryContext(ctx context.Context, repositoryID RepositoryID) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "NexusClient.CreateReleaseRepository", repositoryAttribute(repositoryID))
+	defer func() { span.end(err) }()
+
+	return client.createHostedRepository(ctx, "NexusClient.CreateReleaseRepository", NewHostedRepositoryOptions(repositoryID, ReleasePolicy))
+}
+
+// CreateHostedRepository creates a new hosted Maven2 repository configured by options.  When error is nil, the integer return
+// value is the underlying HTTP response code.
+//
+// CreateHostedRepository uses context.Background internally; to specify the context, use CreateHostedRepositoryContext.
+func (client NexusClient) CreateHostedRepository(options HostedRepositoryOptions) (int, error) {
+	return client.CreateHostedRepositoryContext(context.Background(), options)
+}
+
+// CreateHostedRepositoryContext is like CreateHostedRepository but uses ctx for its HTTP requests and any pending retries.
+func (client NexusClient) CreateHostedRepositoryContext(ctx context.Context, options HostedRepositoryOptions) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "NexusClient.CreateHostedRepository", repositoryAttribute(options.ID))
+	defer func() { span.end(err) }()
+
+	return client.createHostedRepository(ctx, "NexusClient.CreateHostedRepository", options)
+}
+
+// createHostedRepository creates the hosted repository configured by options, attributing any error to op.
+func (client NexusClient) createHostedRepository(ctx context.Context, op string, options HostedRepositoryOptions) (int, error) {
+	options, err := options.withDefaults()
+	if err != nil {
+		return 0, err
+	}
+
 	repo := createrepo{
 		Data: CreateRepoData{
-			Id:                 repositoryID,
-			Name:               string(repositoryID),
+			Id:                 options.ID,
+			Name:               options.Name,
 			Provider:           "maven2",
 			RepoType:           "hosted",
-			RepoPolicy:         "SNAPSHOT",
+			RepoPolicy:         string(options.Policy),
 			ProviderRole:       "org.sonatype.nexus.proxy.repository.Repository",
-			ContentResourceURI: client.BaseURL + "/content/repositories/" + string(repositoryID),
+			ContentResourceURI: client.BaseURL + "/content/repositories/" + string(options.ID),
 			Format:             "maven2",
-			Browseable:         true,
-			Indexable:          true,
-			Exposed:            true,
-			WritePolicy:        "ALLOW_WRITE",
-			NotFoundCacheTTL:   1440,
+			Browseable:         options.Browseable,
+			Indexable:          options.Indexable,
+			Exposed:            options.Exposed,
+			WritePolicy:        string(options.WritePolicy),
+			NotFoundCacheTTL:   options.NotFoundCacheTTL,
 		}}
 
 	data, err := xml.Marshal(&repo)
@@ -122,67 +189,39 @@ func (client NexusClient) CreateSnapshotRepository(repositoryID RepositoryID) (i
 		return 0, err
 	}
 
-	req, err := http.NewRequest("POST", client.BaseURL+"/service/local/repositories", bytes.NewBuffer(data))
-	if err != nil {
-		return 0, err
-	}
-	req.SetBasicAuth(client.Username, client.Password)
-	req.Header.Add("Content-type", "application/xml")
-	req.Header.Add("Accept", "application/json")
-
-	resp, err := client.HttpClient.Do(req)
-	if err != nil {
-		return 0, err
-	}
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return 0, err
-	}
-
-	if resp.StatusCode != 201 {
-		return resp.StatusCode, fmt.Errorf("Client.CreateSnapshotRepository(): unexpected response status: %d (%s)\n", resp.StatusCode, string(body))
-	}
-
-	return resp.StatusCode, nil
+	rc, _, err := client.send(ctx, request{op: op, method: "POST", path: "/service/local/repositories", body: data, contentType: "application/xml", ok: []int{201}})
+	return rc, err
 }
 
 // DeleteRepository deletes the repository with the given repositoryID.
+//
+// DeleteRepository uses context.Background internally; to specify the context, use DeleteRepositoryContext.
 func (client NexusClient) DeleteRepository(repositoryID RepositoryID) (int, error) {
-	retry := retry.New(3, retry.DefaultBackoffFunc)
-	var responseCode int
-	work := func() error {
-		req, err := http.NewRequest("DELETE", client.BaseURL+"/service/local/repositories/"+string(repositoryID), nil)
-		if err != nil {
-			return err
-		}
-		req.SetBasicAuth(client.Username, client.Password)
-		req.Header.Add("Accept", "application/json")
-
-		resp, err := client.HttpClient.Do(req)
-		if err != nil {
-			return err
-		}
-		defer resp.Body.Close()
+	return client.DeleteRepositoryContext(context.Background(), repositoryID)
+}
 
-		if _, err := ioutil.ReadAll(resp.Body); err != nil {
-			return err
-		}
+// DeleteRepositoryContext is like DeleteRepository but uses ctx for its HTTP requests and any pending retries.
+func (client NexusClient) DeleteRepositoryContext(ctx context.Context, repositoryID RepositoryID) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "NexusClient.DeleteRepository", repositoryAttribute(repositoryID))
+	defer func() { span.end(err) }()
 
-		responseCode = resp.StatusCode
-		if responseCode != 204 && responseCode != 404 {
-			return fmt.Errorf("Client.DeleteRepository() response: %d\n", responseCode)
-		}
-		return nil
-	}
-
-	return responseCode, retry.Try(work)
+	rc, _, err := client.send(ctx, request{op: "NexusClient.DeleteRepository", method: "DELETE", path: "/service/local/repositories/" + string(repositoryID), ok: []int{204, 404}})
+	return rc, err
 }
 
 // RepositoryGroup returns a representation of the given repository group ID.
+//
+// RepositoryGroup uses context.Background internally; to specify the context, use RepositoryGroupContext.
 func (client NexusClient) RepositoryGroup(groupID GroupID) (RepositoryGroup, int, error) {
-	repoGroup, rc, err := client.repositoryGroup(groupID)
+	return client.RepositoryGroupContext(context.Background(), groupID)
+}
+
+// RepositoryGroupContext is like RepositoryGroup but uses ctx for its HTTP requests and any pending retries.
+func (client NexusClient) RepositoryGroupContext(ctx context.Context, groupID GroupID) (_ RepositoryGroup, _ int, err error) {
+	ctx, span := client.startOperation(ctx, "NexusClient.RepositoryGroup", groupAttribute(groupID))
+	defer func() { span.end(err) }()
+
+	repoGroup, rc, err := client.repositoryGroup(ctx, "NexusClient.RepositoryGroup", groupID)
 	if err != nil {
 		return RepositoryGroup{}, rc, err
 	}
@@ -193,181 +232,395 @@ func (client NexusClient) RepositoryGroup(groupID GroupID) (RepositoryGroup, int
 }
 
 // AddRepositoryToGroup adds the given repository specified by repositoryID to the repository group specified by groupID.
+//
+// AddRepositoryToGroup uses context.Background internally; to specify the context, use AddRepositoryToGroupContext.
 func (client NexusClient) AddRepositoryToGroup(repositoryID RepositoryID, groupID GroupID) (int, error) {
-	repogroup, rc, err := client.repositoryGroup(groupID)
-	if err != nil {
-		return rc, err
-	}
+	return client.AddRepositoryToGroupContext(context.Background(), repositoryID, groupID)
+}
 
-	// If there is no error preceding this, rc should always be 200.  But say something if it isn't.
-	if rc != 200 {
-		Log.Printf("Nexus Client.AddRepositoryToGroup() response code: %d\n", rc)
-	}
+// AddRepositoryToGroupContext is like AddRepositoryToGroup but uses ctx for its HTTP requests and any pending retries.
+func (client NexusClient) AddRepositoryToGroupContext(ctx context.Context, repositoryID RepositoryID, groupID GroupID) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "NexusClient.AddRepositoryToGroup", repositoryAttribute(repositoryID), groupAttribute(groupID))
+	defer func() { span.end(err) }()
 
-	if repoIsInGroup(repositoryID, repogroup) {
-		Log.Printf("Nexus Client.AddRepositoryToGroup(): RepositoryID %v is already in repository group.  Will not PUT over HTTP.\n", repositoryID)
-		return 0, nil
+	rc, err := client.updateGroup(ctx, "NexusClient.AddRepositoryToGroup", groupID, withMember(repositoryID))
+	if err == nil && rc == 0 {
+		client.logAlreadyInGroup(ctx, "NexusClient.AddRepositoryToGroup", repositoryID, groupID)
 	}
+	return rc, err
+}
 
-	repo := repository{ID: repositoryID, Name: string(repositoryID), ResourceURI: client.BaseURL + "/service/local/repo_groups/" + string(groupID) + "/" + string(repositoryID)}
-	repogroup.Data.Repositories = append(repogroup.Data.Repositories, repo)
+// DeleteRepositoryFromGroup removes the given repository specified by repositoryID from the repository group specified by groupID.
+//
+// RemoveRepositoryFromGroup uses context.Background internally; to specify the context, use RemoveRepositoryFromGroupContext.
+func (client NexusClient) RemoveRepositoryFromGroup(repositoryID RepositoryID, groupID GroupID) (int, error) {
+	return client.RemoveRepositoryFromGroupContext(context.Background(), repositoryID, groupID)
+}
 
-	data, err := json.Marshal(&repogroup)
+// RemoveRepositoryFromGroupContext is like RemoveRepositoryFromGroup but uses ctx for its HTTP requests and any pending retries.
+func (client NexusClient) RemoveRepositoryFromGroupContext(ctx context.Context, repositoryID RepositoryID, groupID GroupID) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "NexusClient.RemoveRepositoryFromGroup", repositoryAttribute(repositoryID), groupAttribute(groupID))
+	defer func() { span.end(err) }()
+
+	return client.updateGroup(ctx, "NexusClient.RemoveRepositoryFromGroup", groupID, withoutMember(repositoryID))
+}
+
+// AddRepositoriesToGroup adds the repositories specified by repositoryIDs to the repository group specified by groupID, in order,
+// with a single PUT.  The outcome for each repository is MemberAdded, MemberAlreadyPresent or MemberNotFound.
+//
+// AddRepositoriesToGroup uses context.Background internally; to specify the context, use AddRepositoriesToGroupContext.
+func (client NexusClient) AddRepositoriesToGroup(repositoryIDs []RepositoryID, groupID GroupID) (map[RepositoryID]MembershipOutcome, int, error) {
+	return client.AddRepositoriesToGroupContext(context.Background(), repositoryIDs, groupID)
+}
+
+// AddRepositoriesToGroupContext is like AddRepositoriesToGroup but uses ctx for its HTTP requests and any pending retries.
+func (client NexusClient) AddRepositoriesToGroupContext(ctx context.Context, repositoryIDs []RepositoryID, groupID GroupID) (_ map[RepositoryID]MembershipOutcome, _ int, err error) {
+	ctx, span := client.startOperation(ctx, "NexusClient.AddRepositoriesToGroup", repositoriesAttribute(AttributeRepositoryIDs, repositoryIDs), groupAttribute(groupID))
+	defer func() { span.end(err) }()
+
+	return client.changeGroupMembership(ctx, "NexusClient.AddRepositoriesToGroup", MembershipChange{Add: repositoryIDs}, groupID)
+}
+
+// RemoveRepositoriesFromGroup removes the repositories specified by repositoryIDs from the repository group specified by groupID with
+// a single PUT.  The outcome for each repository is MemberRemoved or MemberNotAMember.
+//
+// RemoveRepositoriesFromGroup uses context.Background internally; to specify the context, use RemoveRepositoriesFromGroupContext.
+func (client NexusClient) RemoveRepositoriesFromGroup(repositoryIDs []RepositoryID, groupID GroupID) (map[RepositoryID]MembershipOutcome, int, error) {
+	return client.RemoveRepositoriesFromGroupContext(context.Background(), repositoryIDs, groupID)
+}
+
+// RemoveRepositoriesFromGroupContext is like RemoveRepositoriesFromGroup but uses ctx for its HTTP requests and any pending retries.
+func (client NexusClient) RemoveRepositoriesFromGroupContext(ctx context.Context, repositoryIDs []RepositoryID, groupID GroupID) (_ map[RepositoryID]MembershipOutcome, _ int, err error) {
+	ctx, span := client.startOperation(ctx, "NexusClient.RemoveRepositoriesFromGroup", repositoriesAttribute(AttributeRepositoryIDs, repositoryIDs), groupAttribute(groupID))
+	defer func() { span.end(err) }()
+
+	return client.changeGroupMembership(ctx, "NexusClient.RemoveRepositoriesFromGroup", MembershipChange{Remove: repositoryIDs}, groupID)
+}
+
+// ChangeGroupMembership applies change to the members of the repository group specified by groupID with a single PUT, and
+// reports the outcome for each repository named in change.  Nothing is PUT if the group already reflects change, in
+// which case the integer return value is 0.
+//
+// ChangeGroupMembership uses context.Background internally; to specify the context, use ChangeGroupMembershipContext.
+func (client NexusClient) ChangeGroupMembership(change MembershipChange, groupID GroupID) (map[RepositoryID]MembershipOutcome, int, error) {
+	return client.ChangeGroupMembershipContext(context.Background(), change, groupID)
+}
+
+// ChangeGroupMembershipContext is like ChangeGroupMembership but uses ctx for its HTTP requests and any pending retries.
+func (client NexusClient) ChangeGroupMembershipContext(ctx context.Context, change MembershipChange, groupID GroupID) (_ map[RepositoryID]MembershipOutcome, _ int, err error) {
+	ctx, span := client.startOperation(ctx, "NexusClient.ChangeGroupMembership", repositoriesAttribute(AttributeMembersAdded, change.Add), repositoriesAttribute(AttributeMembersRemoved, change.Remove), groupAttribute(groupID))
+	defer func() { span.end(err) }()
+
+	return client.changeGroupMembership(ctx, "NexusClient.ChangeGroupMembership", change, groupID)
+}
+
+// changeGroupMembership applies change to the members of the group specified by groupID, attributing any error to op.
+func (client NexusClient) changeGroupMembership(ctx context.Context, op string, change MembershipChange, groupID GroupID) (map[RepositoryID]MembershipOutcome, int, error) {
+	if err := change.validate(); err != nil {
+		return nil, 0, err
+	}
+	missing, rc, err := client.missingRepositories(ctx, op, change.Add)
 	if err != nil {
-		return 0, err
+		return nil, rc, err
 	}
-
-	req, err := http.NewRequest("PUT", client.BaseURL+"/service/local/repo_groups/"+string(groupID), bytes.NewBuffer(data))
+	apply, outcomes, err := change.apply(missing)
 	if err != nil {
-		return 0, err
+		return nil, 0, err
 	}
-	req.SetBasicAuth(client.Username, client.Password)
-	req.Header.Add("Content-type", "application/json")
-	req.Header.Add("Accept", "application/json")
-
-	resp, err := client.HttpClient.Do(req)
+	rc, err = client.updateGroup(ctx, op, groupID, apply)
 	if err != nil {
-		return 0, err
+		return nil, rc, err
 	}
-	defer resp.Body.Close()
+	return outcomes, rc, nil
+}
 
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return 0, err
+// missingRepositories returns the repositories in ids that are neither repositories nor repository groups on the server,
+// attributing any error to op.  Groups are listed only when some of ids are not repositories.
+func (client NexusClient) missingRepositories(ctx context.Context, op string, ids []RepositoryID) ([]RepositoryID, int, error) {
+	if len(ids) == 0 {
+		return nil, 0, nil
 	}
 
-	if resp.StatusCode != 200 {
-		return resp.StatusCode, fmt.Errorf("Client.AddRepositoryToGroup(): unexpected response status: %d (%s)\n", resp.StatusCode, string(body))
+	var repositories repoList
+	if rc, err := client.getJSON(ctx, op, "/service/local/repositories", &repositories); err != nil {
+		return nil, rc, err
+	}
+	existing := make([]RepositoryID, 0, len(repositories.Data))
+	for _, item := range repositories.Data {
+		existing = append(existing, item.ID)
+	}
+	missing := missingFrom(ids, existing)
+	if len(missing) == 0 {
+		return nil, 0, nil
 	}
 
-	return resp.StatusCode, nil
+	var groups repoGroupList
+	if rc, err := client.getJSON(ctx, op, "/service/local/repo_groups", &groups); err != nil {
+		return nil, rc, err
+	}
+	existing = existing[:0]
+	for _, group := range groups.Data {
+		existing = append(existing, RepositoryID(group.ID))
+	}
+	return missingFrom(missing, existing), 0, nil
 }
 
-// DeleteRepositoryFromGroup removes the given repository specified by repositoryID from the repository group specified by groupID.
-func (client NexusClient) RemoveRepositoryFromGroup(repositoryID RepositoryID, groupID GroupID) (int, error) {
-	repogroup, rc, err := client.repositoryGroup(groupID)
+// CreateRepositoryGroup creates a new repository group configured by options.  When error is nil, the integer return value is
+// the underlying HTTP response code.
+//
+// CreateRepositoryGroup uses context.Background internally; to specify the context, use CreateRepositoryGroupContext.
+func (client NexusClient) CreateRepositoryGroup(options RepositoryGroupOptions) (int, error) {
+	return client.CreateRepositoryGroupContext(context.Background(), options)
+}
+
+// CreateRepositoryGroupContext is like CreateRepositoryGroup but uses ctx for its HTTP requests and any pending retries.
+func (client NexusClient) CreateRepositoryGroupContext(ctx context.Context, options RepositoryGroupOptions) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "NexusClient.CreateRepositoryGroup", groupAttribute(options.ID))
+	defer func() { span.end(err) }()
+
+	options, err = options.withDefaults()
 	if err != nil {
-		return rc, err
-	}
-	if rc != 200 {
-		Log.Printf("Nexus Client.AddRepositoryToGroup() response code: %d\n", rc)
+		return 0, err
 	}
 
-	if repoIsNotInGroup(repositoryID, repogroup) {
-		return 0, nil
+	repogroup := repoGroup{
+		Data: RepositoryGroupData{
+			ID:                 options.ID,
+			Provider:           options.Provider,
+			Name:               options.Name,
+			Repositories:       make([]repository, 0),
+			Format:             "maven2",
+			RepoType:           "group",
+			Exposed:            options.Exposed,
+			ContentResourceURI: client.BaseURL + "/content/groups/" + string(options.ID),
+		}}
+	for _, repositoryID := range options.Repositories {
+		repo := repository{ID: repositoryID, Name: string(repositoryID), ResourceURI: client.BaseURL + "/service/local/repo_groups/" + string(options.ID) + "/" + string(repositoryID)}
+		repogroup.Data.Repositories = append(repogroup.Data.Repositories, repo)
 	}
 
-	removeRepo(repositoryID, &repogroup)
-
 	data, err := json.Marshal(&repogroup)
 	if err != nil {
 		return 0, err
 	}
 
-	retry := retry.New(3, retry.DefaultBackoffFunc)
-	var responseCode int
-	work := func() error {
-		req, err := http.NewRequest("PUT", client.BaseURL+"/service/local/repo_groups/"+string(groupID), bytes.NewBuffer(data))
-		if err != nil {
-			return err
-		}
-		req.SetBasicAuth(client.Username, client.Password)
-		req.Header.Add("Content-type", "application/json")
-		req.Header.Add("Accept", "application/json")
+	rc, _, err := client.send(ctx, request{op: "NexusClient.CreateRepositoryGroup", method: "POST", path: "/service/local/repo_groups", body: data, contentType: "application/json", ok: []int{201}})
+	return rc, err
+}
 
-		resp, err := client.HttpClient.Do(req)
-		if err != nil {
-			return err
-		}
-		defer resp.Body.Close()
+// DeleteRepositoryGroup deletes the repository group with the given groupID.  The member repositories are left intact.
+//
+// DeleteRepositoryGroup uses context.Background internally; to specify the context, use DeleteRepositoryGroupContext.
+func (client NexusClient) DeleteRepositoryGroup(groupID GroupID) (int, error) {
+	return client.DeleteRepositoryGroupContext(context.Background(), groupID)
+}
 
-		body, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return err
-		}
+// DeleteRepositoryGroupContext is like DeleteRepositoryGroup but uses ctx for its HTTP requests and any pending retries.
+func (client NexusClient) DeleteRepositoryGroupContext(ctx context.Context, groupID GroupID) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "NexusClient.DeleteRepositoryGroup", groupAttribute(groupID))
+	defer func() { span.end(err) }()
+
+	rc, _, err := client.send(ctx, request{op: "NexusClient.DeleteRepositoryGroup", method: "DELETE", path: "/service/local/repo_groups/" + string(groupID), ok: []int{204, 404}})
+	return rc, err
+}
+
+// ListRepositories returns the hosted, proxy and virtual repositories that match filter.
+//
+// ListRepositories uses context.Background internally; to specify the context, use ListRepositoriesContext.
+func (client NexusClient) ListRepositories(filter RepositoryFilter) ([]Repository, int, error) {
+	return client.ListRepositoriesContext(context.Background(), filter)
+}
 
-		responseCode = resp.StatusCode
-		if responseCode != 200 {
-			return fmt.Errorf("Client.AddRepositoryToGroup(): unexpected response status: %d (%s)\n", responseCode, string(body))
+// ListRepositoriesContext is like ListRepositories but uses ctx for its HTTP requests and any pending retries.
+func (client NexusClient) ListRepositoriesContext(ctx context.Context, filter RepositoryFilter) (_ []Repository, _ int, err error) {
+	ctx, span := client.startOperation(ctx, "NexusClient.ListRepositories")
+	defer func() { span.end(err) }()
+
+	var list repoList
+	rc, err := client.getJSON(ctx, "NexusClient.ListRepositories", "/service/local/repositories", &list)
+	if err != nil {
+		return nil, rc, err
+	}
+
+	repositories := make([]Repository, 0)
+	for _, item := range list.Data {
+		repo := Repository{
+			ID:                 item.ID,
+			Name:               item.Name,
+			ResourceURI:        item.ResourceURI,
+			Type:               RepositoryType(item.RepoType),
+			Policy:             RepositoryPolicy(item.RepoPolicy),
+			Format:             item.Format,
+			ContentResourceURI: item.ContentResourceURI,
+		}
+		if filter.matchesRepository(repo) {
+			repositories = append(repositories, repo)
 		}
-		return nil
 	}
-	return responseCode, retry.Try(work)
+	return repositories, rc, nil
 }
 
-func (client NexusClient) repositoryGroup(groupID GroupID) (repoGroup, int, error) {
-	retry := retry.New(3, retry.DefaultBackoffFunc)
-	var data []byte
-	var responseCode int
-	work := func() error {
-		req, err := http.NewRequest("GET", client.BaseURL+"/service/local/repo_groups/"+string(groupID), nil)
-		if err != nil {
-			return err
-		}
-		req.SetBasicAuth(client.Username, client.Password)
-		req.Header.Add("Accept", "application/json")
+// ListRepositoryGroups returns the repository groups that match filter.
+//
+// ListRepositoryGroups uses context.Background internally; to specify the context, use ListRepositoryGroupsContext.
+func (client NexusClient) ListRepositoryGroups(filter RepositoryFilter) ([]RepositoryGroup, int, error) {
+	return client.ListRepositoryGroupsContext(context.Background(), filter)
+}
 
-		resp, err := client.HttpClient.Do(req)
-		if err != nil {
-			return err
+// ListRepositoryGroupsContext is like ListRepositoryGroups but uses ctx for its HTTP requests and any pending retries.
+func (client NexusClient) ListRepositoryGroupsContext(ctx context.Context, filter RepositoryFilter) (_ []RepositoryGroup, _ int, err error) {
+	ctx, span := client.startOperation(ctx, "NexusClient.ListRepositoryGroups")
+	defer func() { span.end(err) }()
 
-		}
-		defer resp.Body.Close()
+	var list repoGroupList
+	rc, err := client.getJSON(ctx, "NexusClient.ListRepositoryGroups", "/service/local/repo_groups", &list)
+	if err != nil {
+		return nil, rc, err
+	}
 
-		data, err = ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return err
+	groups := make([]RepositoryGroup, 0)
+	for _, data := range list.Data {
+		group := canonicalize(repoGroup{Data: data})
+		if filter.matchesGroup(group) {
+			groups = append(groups, group)
 		}
+	}
+	return groups, rc, nil
+}
 
-		responseCode = resp.StatusCode
-		if responseCode != 200 {
-			return fmt.Errorf("Client.repositoryGroup() response status: %d (%s)\n", responseCode, string(data))
-		}
-		return nil
+// UploadArtifact deploys to the content of a Nexus 2 repository at /content/repositories/<id>; see IClient.UploadArtifact.
+//
+// UploadArtifact uses context.Background internally; to specify the context, use UploadArtifactContext.
+func (client NexusClient) UploadArtifact(repositoryID RepositoryID, coordinate Coordinate, r io.Reader) (int, error) {
+	return client.UploadArtifactContext(context.Background(), repositoryID, coordinate, r)
+}
+
+// UploadArtifactContext is like UploadArtifact but uses ctx for its HTTP requests and any pending retries.
+func (client NexusClient) UploadArtifactContext(ctx context.Context, repositoryID RepositoryID, coordinate Coordinate, r io.Reader) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "NexusClient.UploadArtifact", repositoryAttribute(repositoryID), coordinateAttribute(coordinate))
+	defer func() { span.end(err) }()
+
+	return client.uploadArtifact(ctx, "NexusClient.UploadArtifact", "/content/repositories/"+string(repositoryID), coordinate, r)
+}
+
+// DownloadArtifact reads a Nexus 2 content URI, such as BaseURL/content/groups/<id>; see IClient.DownloadArtifact.
+//
+// DownloadArtifact uses context.Background internally; to specify the context, use DownloadArtifactContext.
+func (client NexusClient) DownloadArtifact(contentResourceURI string, coordinate Coordinate, w io.Writer) (int, error) {
+	return client.DownloadArtifactContext(context.Background(), contentResourceURI, coordinate, w)
+}
+
+// DownloadArtifactContext is like DownloadArtifact but uses ctx for its HTTP requests and any pending retries.
+func (client NexusClient) DownloadArtifactContext(ctx context.Context, contentResourceURI string, coordinate Coordinate, w io.Writer) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "NexusClient.DownloadArtifact", coordinateAttribute(coordinate))
+	defer func() { span.end(err) }()
+
+	return client.downloadArtifact(ctx, "NexusClient.DownloadArtifact", contentResourceURI, coordinate, w)
+}
+
+// ResolveSnapshot reads the metadata under a Nexus 2 content URI; see IClient.ResolveSnapshot.
+//
+// ResolveSnapshot uses context.Background internally; to specify the context, use ResolveSnapshotContext.
+func (client NexusClient) ResolveSnapshot(contentResourceURI string, coordinate Coordinate) (Coordinate, int, error) {
+	return client.ResolveSnapshotContext(context.Background(), contentResourceURI, coordinate)
+}
+
+// ResolveSnapshotContext is like ResolveSnapshot but uses ctx for its HTTP requests and any pending retries.
+func (client NexusClient) ResolveSnapshotContext(ctx context.Context, contentResourceURI string, coordinate Coordinate) (_ Coordinate, _ int, err error) {
+	ctx, span := client.startOperation(ctx, "NexusClient.ResolveSnapshot", coordinateAttribute(coordinate))
+	defer func() { span.end(err) }()
+
+	return client.resolveSnapshot(ctx, "NexusClient.ResolveSnapshot", contentResourceURI, coordinate)
+}
+
+// repositoryGroup retrieves the repository group specified by groupID, attributing any error to op.
+func (client NexusClient) repositoryGroup(ctx context.Context, op string, groupID GroupID) (repoGroup, int, error) {
+	var repogroup repoGroup
+	rc, err := client.getJSON(ctx, op, "/service/local/repo_groups/"+string(groupID), &repogroup)
+	if err != nil {
+		return repoGroup{}, rc, err
 	}
-	if err := retry.Try(work); err != nil {
-		return repoGroup{}, responseCode, err
+	return repogroup, rc, nil
+}
+
+// updateGroup applies change to the members of the repository group specified by groupID, together with any changes to the
+// same group made concurrently through this client; see groupBatcher.  A client without a batcher applies change alone.
+func (client NexusClient) updateGroup(ctx context.Context, op string, groupID GroupID, change func([]RepositoryID) ([]RepositoryID, error)) (int, error) {
+	if client.groups == nil {
+		return client.writeGroup(ctx, op, groupID, change)
+	}
+	return client.groups.do(ctx, groupID, change, func(ctx context.Context, change func([]RepositoryID) ([]RepositoryID, error)) (int, error) {
+		return client.writeGroup(ctx, op, groupID, change)
+	})
+}
+
+// writeGroup applies change to the members of the repository group specified by groupID, holding the group's lock file if
+// GroupLockDir is set; see updateGroupMembers.
+func (client NexusClient) writeGroup(ctx context.Context, op string, groupID GroupID, change func([]RepositoryID) ([]RepositoryID, error)) (int, error) {
+	if client.GroupLockDir != "" {
+		unlock, err := lockGroup(ctx, client.GroupLockDir, groupID)
+		if err != nil {
+			return 0, err
+		}
+		defer unlock()
 	}
 
 	var repogroup repoGroup
-	if err := json.Unmarshal(data, &repogroup); err != nil {
-		return repoGroup{}, 0, err
+	read := func() ([]RepositoryID, int, error) {
+		var rc int
+		var err error
+		repogroup, rc, err = client.repositoryGroup(ctx, op, groupID)
+		return memberIDs(repogroup), rc, err
+	}
+	write := func(members []RepositoryID) (int, error) {
+		client.setMembers(&repogroup, members)
+		return client.putRepositoryGroup(ctx, op, groupID, repogroup)
 	}
-	return repogroup, responseCode, nil
+	return client.updateGroupMembers(ctx, op, groupID, read, write, change)
 }
 
-func repoIsInGroup(repositoryID RepositoryID, group repoGroup) bool {
-	for _, repo := range group.Data.Repositories {
-		if repo.ID == repositoryID {
-			return true
-		}
+// putRepositoryGroup writes repogroup back to the server.
+func (client NexusClient) putRepositoryGroup(ctx context.Context, op string, groupID GroupID, repogroup repoGroup) (int, error) {
+	data, err := json.Marshal(&repogroup)
+	if err != nil {
+		return 0, err
 	}
-	return false
+
+	rc, _, err := client.send(ctx, request{op: op, method: "PUT", path: "/service/local/repo_groups/" + string(groupID), body: data, contentType: "application/json"})
+	return rc, err
 }
 
-func repoIsNotInGroup(repositoryID RepositoryID, group repoGroup) bool {
+// setMembers replaces the members of group with members, keeping the details Nexus reported for existing members.
+func (client NexusClient) setMembers(group *repoGroup, members []RepositoryID) {
+	existing := make(map[RepositoryID]repository)
 	for _, repo := range group.Data.Repositories {
-		if repo.ID == repositoryID {
-			return false
+		existing[repo.ID] = repo
+	}
+
+	ra := make([]repository, 0, len(members))
+	for _, id := range members {
+		repo, ok := existing[id]
+		if !ok {
+			repo = repository{ID: id, Name: string(id), ResourceURI: client.BaseURL + "/service/local/repo_groups/" + string(group.Data.ID) + "/" + string(id)}
 		}
+		ra = append(ra, repo)
 	}
-	return true
+	group.Data.Repositories = ra
 }
 
-func removeRepo(repositoryID RepositoryID, group *repoGroup) {
-	ra := make([]repository, 0)
+func memberIDs(group repoGroup) []RepositoryID {
+	ids := make([]RepositoryID, 0, len(group.Data.Repositories))
 	for _, repo := range group.Data.Repositories {
-		if repo.ID != repositoryID {
-			ra = append(ra, repo)
-		}
+		ids = append(ids, repo.ID)
 	}
-	group.Data.Repositories = ra
+	return ids
 }
 
 func canonicalize(nexusRepositoryGroup repoGroup) RepositoryGroup {
 	c := RepositoryGroup{
 		ID:                 nexusRepositoryGroup.Data.ID,
 		Name:               nexusRepositoryGroup.Data.Name,
+		Format:             nexusRepositoryGroup.Data.Format,
 		ContentResourceURI: nexusRepositoryGroup.Data.ContentResourceURI,
 		Repositories:       make([]Repository, 0),
 	}
@@ -0,0 +1,571 @@
+package maventools
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+type (
+	// The type posted to create a Maven hosted repository.
+	nexus3HostedRepo struct {
+		Name    RepositoryID  `json:"name"`
+		Online  bool          `json:"online"`
+		Storage nexus3Storage `json:"storage"`
+		Maven   nexus3Maven   `json:"maven"`
+	}
+
+	// The type retrieved or put to read or mutate a Maven group repository.  Format, Type and URL are
+	// returned by the server on reads but are not part of the update payload.
+	nexus3GroupRepo struct {
+		Name    GroupID       `json:"name"`
+		Format  string        `json:"format,omitempty"`
+		Type    string        `json:"type,omitempty"`
+		URL     string        `json:"url,omitempty"`
+		Online  bool          `json:"online"`
+		Storage nexus3Storage `json:"storage"`
+		Group   nexus3Group   `json:"group"`
+	}
+
+	nexus3Storage struct {
+		BlobStoreName               string `json:"blobStoreName"`
+		StrictContentTypeValidation bool   `json:"strictContentTypeValidation"`
+		WritePolicy                 string `json:"writePolicy,omitempty"`
+	}
+
+	nexus3Maven struct {
+		VersionPolicy string `json:"versionPolicy"`
+		LayoutPolicy  string `json:"layoutPolicy"`
+	}
+
+	nexus3Group struct {
+		MemberNames []RepositoryID `json:"memberNames"`
+	}
+
+	// An entry of the repository settings list.  Maven is present for hosted and proxy repositories, Group for groups.
+	nexus3RepoSettings struct {
+		Name   string       `json:"name"`
+		Format string       `json:"format"`
+		Type   string       `json:"type"`
+		URL    string       `json:"url"`
+		Maven  *nexus3Maven `json:"maven"`
+		Group  *nexus3Group `json:"group"`
+	}
+
+	Nexus3Client struct {
+		ClientConfig
+	}
+)
+
+// Nexus 3 names for the write policies of Nexus 2.
+var nexus3WritePolicies = map[WritePolicy]string{
+	AllowWrite:     "ALLOW",
+	AllowWriteOnce: "ALLOW_ONCE",
+	ReadOnly:       "DENY",
+}
+
+// NewNexus3Client creates a new Nexus 3 client implementation on which subsequent service methods are called.  The baseURL typically takes
+// the form http://host:port.  username and password are the credentials of an admin user capable of creating and mutating repositories
+// within Nexus.
+func NewNexus3Client(baseURL, username, password string) Nexus3Client {
+	return Nexus3Client{ClientConfig{BaseURL: baseURL, Username: username, Password: password, HttpClient: &http.Client{}}}
+}
+
+// RepositoryExists checks whether a given repository specified by repositoryID exists.
+//
+// RepositoryExists uses context.Background internally; to specify the context, use RepositoryExistsContext.
+func (client Nexus3Client) RepositoryExists(repositoryID RepositoryID) (bool, error) {
+	return client.RepositoryExistsContext(context.Background(), repositoryID)
+}
+
+// RepositoryExistsContext is like RepositoryExists but uses ctx for its HTTP requests and any pending retries.
+func (client Nexus3Client) RepositoryExistsContext(ctx context.Context, repositoryID RepositoryID) (_ bool, err error) {
+	ctx, span := client.startOperation(ctx, "Nexus3Client.RepositoryExists", repositoryAttribute(repositoryID))
+	defer func() { span.end(err) }()
+
+	rc, _, err := client.send(ctx, request{op: "Nexus3Client.RepositoryExists", method: "GET", path: "/service/rest/v1/repositories/" + string(repositoryID), ok: []int{http.StatusOK, http.StatusNotFound}})
+	return rc == http.StatusOK, err
+}
+
+// CreateSnapshotRepository creates a new hosted Maven SNAPSHOT repository with the given repositoryID in the default blob store.
+// When error is nil, the integer return value is the underlying HTTP response code.
+//
+// CreateSnapshotRepository uses context.Background internally; to specify the context, use CreateSnapshotRepositoryContext.
+func (client Nexus3Client) CreateSnapshotRepository(repositoryID RepositoryID) (int, error) {
+	return client.CreateSnapshotRepositoryContext(context.Background(), repositoryID)
+}
+
+// CreateSnapshotRepositoryContext is like CreateSnapshotRepository but uses ctx for its HTTP requests and any pending retries.
+func (client Nexus3Client) CreateSnapshotRepositoryContext(ctx context.Context, repositoryID RepositoryID) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "Nexus3Client.CreateSnapshotRepository", repositoryAttribute(repositoryID))
+	defer func() { span.end(err) }()
+
+	return client.createHostedRepository(ctx, "Nexus3Client.CreateSnapshotRepository", NewHostedRepositoryOptions(repositoryID, SnapshotPolicy))
+}
+
+// CreateReleaseRepository creates a new hosted Maven RELEASE repository with the given repositoryID in the default blob store.
+// When error is nil, the integer return value is the underlying HTTP response code.
+//
+// CreateReleaseRepository uses context.Background internally; to specify the context, use CreateReleaseRepositoryContext.
+func (client Nexus3Client) CreateReleaseRepository(repositoryID RepositoryID) (int, error) {
+	return client.CreateReleaseRepositoryContext(context.Background(), repositoryID)
+}
+
+// CreateReleaseRepositoryContext is like CreateReleaseRepository but uses ctx for its HTTP requests and any pending retries.
+func (client Nexus3Client) CreateReleaseRepositoryContext(ctx context.Context, repositoryID RepositoryID) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "Nexus3Client.CreateReleaseRepository", repositoryAttribute(repositoryID))
+	defer func() { span.end(err) }()
+
+	return client.createHostedRepository(ctx, "Nexus3Client.CreateReleaseRepository", NewHostedRepositoryOptions(repositoryID, ReleasePolicy))
+}
+
+// CreateHostedRepository creates a new hosted Maven repository configured by options in the default blob store.  Nexus 3
+// repositories are identified by name alone, so the display name is ignored, as are Browseable, Indexable and
+// NotFoundCacheTTL; Exposed determines whether the repository is online.  When error is nil, the integer return value is
+// the underlying HTTP response code.
+//
+// CreateHostedRepository uses context.Background internally; to specify the context, use CreateHostedRepositoryContext.
+func (client Nexus3Client) CreateHostedRepository(options HostedRepositoryOptions) (int, error) {
+	return client.CreateHostedRepositoryContext(context.Background(), options)
+}
+
+// CreateHostedRepositoryContext is like CreateHostedRepository but uses ctx for its HTTP requests and any pending retries.
+func (client Nexus3Client) CreateHostedRepositoryContext(ctx context.Context, options HostedRepositoryOptions) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "Nexus3Client.CreateHostedRepository", repositoryAttribute(options.ID))
+	defer func() { span.end(err) }()
+
+	return client.createHostedRepository(ctx, "Nexus3Client.CreateHostedRepository", options)
+}
+
+// createHostedRepository creates the hosted repository configured by options, attributing any error to op.
+func (client Nexus3Client) createHostedRepository(ctx context.Context, op string, options HostedRepositoryOptions) (int, error) {
+	options, err := options.withDefaults()
+	if err != nil {
+		return 0, err
+	}
+
+	repo := nexus3HostedRepo{
+		Name:   options.ID,
+		Online: options.Exposed,
+		Storage: nexus3Storage{
+			BlobStoreName:               "default",
+			StrictContentTypeValidation: true,
+			WritePolicy:                 nexus3WritePolicies[options.WritePolicy],
+		},
+		Maven: nexus3Maven{
+			VersionPolicy: string(options.Policy),
+			LayoutPolicy:  "STRICT",
+		},
+	}
+
+	data, err := json.Marshal(&repo)
+	if err != nil {
+		return 0, err
+	}
+
+	rc, _, err := client.send(ctx, request{op: op, method: "POST", path: "/service/rest/v1/repositories/maven/hosted", body: data, contentType: "application/json", ok: []int{201}})
+	return rc, err
+}
+
+// DeleteRepository deletes the repository with the given repositoryID.
+//
+// DeleteRepository uses context.Background internally; to specify the context, use DeleteRepositoryContext.
+func (client Nexus3Client) DeleteRepository(repositoryID RepositoryID) (int, error) {
+	return client.DeleteRepositoryContext(context.Background(), repositoryID)
+}
+
+// DeleteRepositoryContext is like DeleteRepository but uses ctx for its HTTP requests and any pending retries.
+func (client Nexus3Client) DeleteRepositoryContext(ctx context.Context, repositoryID RepositoryID) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "Nexus3Client.DeleteRepository", repositoryAttribute(repositoryID))
+	defer func() { span.end(err) }()
+
+	return client.deleteRepository(ctx, "Nexus3Client.DeleteRepository", repositoryID)
+}
+
+// deleteRepository deletes the repository with the given repositoryID, attributing any error to op.
+func (client Nexus3Client) deleteRepository(ctx context.Context, op string, repositoryID RepositoryID) (int, error) {
+	rc, _, err := client.send(ctx, request{op: op, method: "DELETE", path: "/service/rest/v1/repositories/" + string(repositoryID), ok: []int{204, 404}})
+	return rc, err
+}
+
+// RepositoryGroup returns a representation of the given Maven group repository.  The group's member names become the
+// Repositories of the returned RepositoryGroup, in member order.
+//
+// RepositoryGroup uses context.Background internally; to specify the context, use RepositoryGroupContext.
+func (client Nexus3Client) RepositoryGroup(groupID GroupID) (RepositoryGroup, int, error) {
+	return client.RepositoryGroupContext(context.Background(), groupID)
+}
+
+// RepositoryGroupContext is like RepositoryGroup but uses ctx for its HTTP requests and any pending retries.
+func (client Nexus3Client) RepositoryGroupContext(ctx context.Context, groupID GroupID) (_ RepositoryGroup, _ int, err error) {
+	ctx, span := client.startOperation(ctx, "Nexus3Client.RepositoryGroup", groupAttribute(groupID))
+	defer func() { span.end(err) }()
+
+	group, rc, err := client.groupRepository(ctx, "Nexus3Client.RepositoryGroup", groupID)
+	if err != nil {
+		return RepositoryGroup{}, rc, err
+	}
+	if rc != 200 {
+		return RepositoryGroup{}, rc, nil
+	}
+	return client.canonicalize(group), rc, nil
+}
+
+// AddRepositoryToGroup adds the given repository specified by repositoryID to the group repository specified by groupID.
+//
+// AddRepositoryToGroup uses context.Background internally; to specify the context, use AddRepositoryToGroupContext.
+func (client Nexus3Client) AddRepositoryToGroup(repositoryID RepositoryID, groupID GroupID) (int, error) {
+	return client.AddRepositoryToGroupContext(context.Background(), repositoryID, groupID)
+}
+
+// AddRepositoryToGroupContext is like AddRepositoryToGroup but uses ctx for its HTTP requests and any pending retries.
+func (client Nexus3Client) AddRepositoryToGroupContext(ctx context.Context, repositoryID RepositoryID, groupID GroupID) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "Nexus3Client.AddRepositoryToGroup", repositoryAttribute(repositoryID), groupAttribute(groupID))
+	defer func() { span.end(err) }()
+
+	rc, err := client.updateGroup(ctx, "Nexus3Client.AddRepositoryToGroup", groupID, withMember(repositoryID))
+	if err == nil && rc == 0 {
+		client.logAlreadyInGroup(ctx, "Nexus3Client.AddRepositoryToGroup", repositoryID, groupID)
+	}
+	return rc, err
+}
+
+// RemoveRepositoryFromGroup removes the given repository specified by repositoryID from the group repository specified by groupID.
+//
+// RemoveRepositoryFromGroup uses context.Background internally; to specify the context, use RemoveRepositoryFromGroupContext.
+func (client Nexus3Client) RemoveRepositoryFromGroup(repositoryID RepositoryID, groupID GroupID) (int, error) {
+	return client.RemoveRepositoryFromGroupContext(context.Background(), repositoryID, groupID)
+}
+
+// RemoveRepositoryFromGroupContext is like RemoveRepositoryFromGroup but uses ctx for its HTTP requests and any pending retries.
+func (client Nexus3Client) RemoveRepositoryFromGroupContext(ctx context.Context, repositoryID RepositoryID, groupID GroupID) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "Nexus3Client.RemoveRepositoryFromGroup", repositoryAttribute(repositoryID), groupAttribute(groupID))
+	defer func() { span.end(err) }()
+
+	return client.updateGroup(ctx, "Nexus3Client.RemoveRepositoryFromGroup", groupID, withoutMember(repositoryID))
+}
+
+// AddRepositoriesToGroup adds the repositories specified by repositoryIDs to the group repository specified by groupID, in order,
+// with a single PUT.  The outcome for each repository is MemberAdded, MemberAlreadyPresent or MemberNotFound.
+//
+// AddRepositoriesToGroup uses context.Background internally; to specify the context, use AddRepositoriesToGroupContext.
+func (client Nexus3Client) AddRepositoriesToGroup(repositoryIDs []RepositoryID, groupID GroupID) (map[RepositoryID]MembershipOutcome, int, error) {
+	return client.AddRepositoriesToGroupContext(context.Background(), repositoryIDs, groupID)
+}
+
+// AddRepositoriesToGroupContext is like AddRepositoriesToGroup but uses ctx for its HTTP requests and any pending retries.
+func (client Nexus3Client) AddRepositoriesToGroupContext(ctx context.Context, repositoryIDs []RepositoryID, groupID GroupID) (_ map[RepositoryID]MembershipOutcome, _ int, err error) {
+	ctx, span := client.startOperation(ctx, "Nexus3Client.AddRepositoriesToGroup", repositoriesAttribute(AttributeRepositoryIDs, repositoryIDs), groupAttribute(groupID))
+	defer func() { span.end(err) }()
+
+	return client.changeGroupMembership(ctx, "Nexus3Client.AddRepositoriesToGroup", MembershipChange{Add: repositoryIDs}, groupID)
+}
+
+// RemoveRepositoriesFromGroup removes the repositories specified by repositoryIDs from the group repository specified by groupID with
+// a single PUT.  The outcome for each repository is MemberRemoved or MemberNotAMember.
+//
+// RemoveRepositoriesFromGroup uses context.Background internally; to specify the context, use RemoveRepositoriesFromGroupContext.
+func (client Nexus3Client) RemoveRepositoriesFromGroup(repositoryIDs []RepositoryID, groupID GroupID) (map[RepositoryID]MembershipOutcome, int, error) {
+	return client.RemoveRepositoriesFromGroupContext(context.Background(), repositoryIDs, groupID)
+}
+
+// RemoveRepositoriesFromGroupContext is like RemoveRepositoriesFromGroup but uses ctx for its HTTP requests and any pending retries.
+func (client Nexus3Client) RemoveRepositoriesFromGroupContext(ctx context.Context, repositoryIDs []RepositoryID, groupID GroupID) (_ map[RepositoryID]MembershipOutcome, _ int, err error) {
+	ctx, span := client.startOperation(ctx, "Nexus3Client.RemoveRepositoriesFromGroup", repositoriesAttribute(AttributeRepositoryIDs, repositoryIDs), groupAttribute(groupID))
+	defer func() { span.end(err) }()
+
+	return client.changeGroupMembership(ctx, "Nexus3Client.RemoveRepositoriesFromGroup", MembershipChange{Remove: repositoryIDs}, groupID)
+}
+
+// ChangeGroupMembership applies change to the members of the group repository specified by groupID with a single PUT, and
+// reports the outcome for each repository named in change.  Nothing is PUT if the group already reflects change, in
+// which case the integer return value is 0.
+//
+// ChangeGroupMembership uses context.Background internally; to specify the context, use ChangeGroupMembershipContext.
+func (client Nexus3Client) ChangeGroupMembership(change MembershipChange, groupID GroupID) (map[RepositoryID]MembershipOutcome, int, error) {
+	return client.ChangeGroupMembershipContext(context.Background(), change, groupID)
+}
+
+// ChangeGroupMembershipContext is like ChangeGroupMembership but uses ctx for its HTTP requests and any pending retries.
+func (client Nexus3Client) ChangeGroupMembershipContext(ctx context.Context, change MembershipChange, groupID GroupID) (_ map[RepositoryID]MembershipOutcome, _ int, err error) {
+	ctx, span := client.startOperation(ctx, "Nexus3Client.ChangeGroupMembership", repositoriesAttribute(AttributeMembersAdded, change.Add), repositoriesAttribute(AttributeMembersRemoved, change.Remove), groupAttribute(groupID))
+	defer func() { span.end(err) }()
+
+	return client.changeGroupMembership(ctx, "Nexus3Client.ChangeGroupMembership", change, groupID)
+}
+
+// changeGroupMembership applies change to the members of the group specified by groupID, attributing any error to op.
+func (client Nexus3Client) changeGroupMembership(ctx context.Context, op string, change MembershipChange, groupID GroupID) (map[RepositoryID]MembershipOutcome, int, error) {
+	if err := change.validate(); err != nil {
+		return nil, 0, err
+	}
+	missing, rc, err := client.missingRepositories(ctx, op, change.Add)
+	if err != nil {
+		return nil, rc, err
+	}
+	apply, outcomes, err := change.apply(missing)
+	if err != nil {
+		return nil, 0, err
+	}
+	rc, err = client.updateGroup(ctx, op, groupID, apply)
+	if err != nil {
+		return nil, rc, err
+	}
+	return outcomes, rc, nil
+}
+
+// missingRepositories returns the repositories in ids that do not exist on the server, attributing any error to op.
+func (client Nexus3Client) missingRepositories(ctx context.Context, op string, ids []RepositoryID) ([]RepositoryID, int, error) {
+	if len(ids) == 0 {
+		return nil, 0, nil
+	}
+
+	var list []nexus3RepoSettings
+	if rc, err := client.getJSON(ctx, op, "/service/rest/v1/repositorySettings", &list); err != nil {
+		return nil, rc, err
+	}
+	existing := make([]RepositoryID, 0, len(list))
+	for _, settings := range list {
+		existing = append(existing, RepositoryID(settings.Name))
+	}
+	return missingFrom(ids, existing), 0, nil
+}
+
+// CreateRepositoryGroup creates a new Maven group repository configured by options in the default blob store.  Nexus 3
+// repositories are identified by name alone, so the display name and provider are ignored; Exposed determines whether the
+// group is online.  When error is nil, the integer return value is the underlying HTTP response code.
+//
+// CreateRepositoryGroup uses context.Background internally; to specify the context, use CreateRepositoryGroupContext.
+func (client Nexus3Client) CreateRepositoryGroup(options RepositoryGroupOptions) (int, error) {
+	return client.CreateRepositoryGroupContext(context.Background(), options)
+}
+
+// CreateRepositoryGroupContext is like CreateRepositoryGroup but uses ctx for its HTTP requests and any pending retries.
+func (client Nexus3Client) CreateRepositoryGroupContext(ctx context.Context, options RepositoryGroupOptions) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "Nexus3Client.CreateRepositoryGroup", groupAttribute(options.ID))
+	defer func() { span.end(err) }()
+
+	options, err = options.withDefaults()
+	if err != nil {
+		return 0, err
+	}
+
+	group := nexus3GroupRepo{
+		Name:   options.ID,
+		Online: options.Exposed,
+		Storage: nexus3Storage{
+			BlobStoreName:               "default",
+			StrictContentTypeValidation: true,
+		},
+		Group: nexus3Group{MemberNames: make([]RepositoryID, 0)},
+	}
+	group.Group.MemberNames = append(group.Group.MemberNames, options.Repositories...)
+
+	data, err := json.Marshal(&group)
+	if err != nil {
+		return 0, err
+	}
+
+	rc, _, err := client.send(ctx, request{op: "Nexus3Client.CreateRepositoryGroup", method: "POST", path: "/service/rest/v1/repositories/maven/group", body: data, contentType: "application/json", ok: []int{201}})
+	return rc, err
+}
+
+// DeleteRepositoryGroup deletes the group repository with the given groupID.  The member repositories are left intact.
+//
+// DeleteRepositoryGroup uses context.Background internally; to specify the context, use DeleteRepositoryGroupContext.
+func (client Nexus3Client) DeleteRepositoryGroup(groupID GroupID) (int, error) {
+	return client.DeleteRepositoryGroupContext(context.Background(), groupID)
+}
+
+// DeleteRepositoryGroupContext is like DeleteRepositoryGroup but uses ctx for its HTTP requests and any pending retries.
+func (client Nexus3Client) DeleteRepositoryGroupContext(ctx context.Context, groupID GroupID) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "Nexus3Client.DeleteRepositoryGroup", groupAttribute(groupID))
+	defer func() { span.end(err) }()
+
+	// Nexus 3 deletes group and hosted repositories through the same resource.
+	return client.deleteRepository(ctx, "Nexus3Client.DeleteRepositoryGroup", RepositoryID(groupID))
+}
+
+// ListRepositories returns the Maven hosted and proxy repositories that match filter.
+//
+// ListRepositories uses context.Background internally; to specify the context, use ListRepositoriesContext.
+func (client Nexus3Client) ListRepositories(filter RepositoryFilter) ([]Repository, int, error) {
+	return client.ListRepositoriesContext(context.Background(), filter)
+}
+
+// ListRepositoriesContext is like ListRepositories but uses ctx for its HTTP requests and any pending retries.
+func (client Nexus3Client) ListRepositoriesContext(ctx context.Context, filter RepositoryFilter) (_ []Repository, _ int, err error) {
+	ctx, span := client.startOperation(ctx, "Nexus3Client.ListRepositories")
+	defer func() { span.end(err) }()
+
+	var list []nexus3RepoSettings
+	rc, err := client.getJSON(ctx, "Nexus3Client.ListRepositories", "/service/rest/v1/repositorySettings", &list)
+	if err != nil {
+		return nil, rc, err
+	}
+
+	repositories := make([]Repository, 0)
+	for _, settings := range list {
+		if settings.Format != "maven2" || settings.Type == "group" {
+			continue
+		}
+		repo := Repository{
+			ID:                 RepositoryID(settings.Name),
+			Name:               settings.Name,
+			ResourceURI:        client.BaseURL + "/service/rest/v1/repositories/" + settings.Name,
+			Type:               RepositoryType(settings.Type),
+			Format:             settings.Format,
+			ContentResourceURI: settings.URL,
+		}
+		if settings.Maven != nil {
+			repo.Policy = RepositoryPolicy(settings.Maven.VersionPolicy)
+		}
+		if filter.matchesRepository(repo) {
+			repositories = append(repositories, repo)
+		}
+	}
+	return repositories, rc, nil
+}
+
+// ListRepositoryGroups returns the Maven group repositories that match filter.
+//
+// ListRepositoryGroups uses context.Background internally; to specify the context, use ListRepositoryGroupsContext.
+func (client Nexus3Client) ListRepositoryGroups(filter RepositoryFilter) ([]RepositoryGroup, int, error) {
+	return client.ListRepositoryGroupsContext(context.Background(), filter)
+}
+
+// ListRepositoryGroupsContext is like ListRepositoryGroups but uses ctx for its HTTP requests and any pending retries.
+func (client Nexus3Client) ListRepositoryGroupsContext(ctx context.Context, filter RepositoryFilter) (_ []RepositoryGroup, _ int, err error) {
+	ctx, span := client.startOperation(ctx, "Nexus3Client.ListRepositoryGroups")
+	defer func() { span.end(err) }()
+
+	var list []nexus3RepoSettings
+	rc, err := client.getJSON(ctx, "Nexus3Client.ListRepositoryGroups", "/service/rest/v1/repositorySettings", &list)
+	if err != nil {
+		return nil, rc, err
+	}
+
+	groups := make([]RepositoryGroup, 0)
+	for _, settings := range list {
+		if settings.Format != "maven2" || settings.Type != "group" {
+			continue
+		}
+		group := nexus3GroupRepo{Name: GroupID(settings.Name), Format: settings.Format, Type: settings.Type, URL: settings.URL}
+		if settings.Group != nil {
+			group.Group = *settings.Group
+		}
+		canonical := client.canonicalize(group)
+		if filter.matchesGroup(canonical) {
+			groups = append(groups, canonical)
+		}
+	}
+	return groups, rc, nil
+}
+
+// UploadArtifact deploys to the content of a Nexus 3 repository at /repository/<id>; see IClient.UploadArtifact.
+//
+// UploadArtifact uses context.Background internally; to specify the context, use UploadArtifactContext.
+func (client Nexus3Client) UploadArtifact(repositoryID RepositoryID, coordinate Coordinate, r io.Reader) (int, error) {
+	return client.UploadArtifactContext(context.Background(), repositoryID, coordinate, r)
+}
+
+// UploadArtifactContext is like UploadArtifact but uses ctx for its HTTP requests and any pending retries.
+func (client Nexus3Client) UploadArtifactContext(ctx context.Context, repositoryID RepositoryID, coordinate Coordinate, r io.Reader) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "Nexus3Client.UploadArtifact", repositoryAttribute(repositoryID), coordinateAttribute(coordinate))
+	defer func() { span.end(err) }()
+
+	return client.uploadArtifact(ctx, "Nexus3Client.UploadArtifact", "/repository/"+string(repositoryID), coordinate, r)
+}
+
+// DownloadArtifact reads a Nexus 3 content URI, such as BaseURL/repository/<id>; see IClient.DownloadArtifact.
+//
+// DownloadArtifact uses context.Background internally; to specify the context, use DownloadArtifactContext.
+func (client Nexus3Client) DownloadArtifact(contentResourceURI string, coordinate Coordinate, w io.Writer) (int, error) {
+	return client.DownloadArtifactContext(context.Background(), contentResourceURI, coordinate, w)
+}
+
+// DownloadArtifactContext is like DownloadArtifact but uses ctx for its HTTP requests and any pending retries.
+func (client Nexus3Client) DownloadArtifactContext(ctx context.Context, contentResourceURI string, coordinate Coordinate, w io.Writer) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "Nexus3Client.DownloadArtifact", coordinateAttribute(coordinate))
+	defer func() { span.end(err) }()
+
+	return client.downloadArtifact(ctx, "Nexus3Client.DownloadArtifact", contentResourceURI, coordinate, w)
+}
+
+// ResolveSnapshot reads the metadata under a Nexus 3 content URI; see IClient.ResolveSnapshot.
+//
+// ResolveSnapshot uses context.Background internally; to specify the context, use ResolveSnapshotContext.
+func (client Nexus3Client) ResolveSnapshot(contentResourceURI string, coordinate Coordinate) (Coordinate, int, error) {
+	return client.ResolveSnapshotContext(context.Background(), contentResourceURI, coordinate)
+}
+
+// ResolveSnapshotContext is like ResolveSnapshot but uses ctx for its HTTP requests and any pending retries.
+func (client Nexus3Client) ResolveSnapshotContext(ctx context.Context, contentResourceURI string, coordinate Coordinate) (_ Coordinate, _ int, err error) {
+	ctx, span := client.startOperation(ctx, "Nexus3Client.ResolveSnapshot", coordinateAttribute(coordinate))
+	defer func() { span.end(err) }()
+
+	return client.resolveSnapshot(ctx, "Nexus3Client.ResolveSnapshot", contentResourceURI, coordinate)
+}
+
+// groupRepository retrieves the group repository specified by groupID, attributing any error to op.
+func (client Nexus3Client) groupRepository(ctx context.Context, op string, groupID GroupID) (nexus3GroupRepo, int, error) {
+	var group nexus3GroupRepo
+	rc, err := client.getJSON(ctx, op, "/service/rest/v1/repositories/maven/group/"+string(groupID), &group)
+	if err != nil {
+		return nexus3GroupRepo{}, rc, err
+	}
+	return group, rc, nil
+}
+
+// updateGroup applies change to the members of the group repository specified by groupID; see updateGroupMembers.
+func (client Nexus3Client) updateGroup(ctx context.Context, op string, groupID GroupID, change func([]RepositoryID) ([]RepositoryID, error)) (int, error) {
+	var group nexus3GroupRepo
+	read := func() ([]RepositoryID, int, error) {
+		var rc int
+		var err error
+		group, rc, err = client.groupRepository(ctx, op, groupID)
+		return group.Group.MemberNames, rc, err
+	}
+	write := func(members []RepositoryID) (int, error) {
+		group.Group.MemberNames = members
+		return client.putGroupRepository(ctx, op, groupID, group)
+	}
+	return client.updateGroupMembers(ctx, op, groupID, read, write, change)
+}
+
+// putGroupRepository writes group back to the server.
+func (client Nexus3Client) putGroupRepository(ctx context.Context, op string, groupID GroupID, group nexus3GroupRepo) (int, error) {
+	data, err := json.Marshal(group.updatePayload())
+	if err != nil {
+		return 0, err
+	}
+
+	rc, _, err := client.send(ctx, request{op: op, method: "PUT", path: "/service/rest/v1/repositories/maven/group/" + string(groupID), body: data, contentType: "application/json", ok: []int{204}})
+	return rc, err
+}
+
+func (client Nexus3Client) canonicalize(group nexus3GroupRepo) RepositoryGroup {
+	c := RepositoryGroup{
+		ID:                 group.Name,
+		Name:               string(group.Name),
+		Format:             group.Format,
+		ContentResourceURI: group.URL,
+		Repositories:       make([]Repository, 0),
+	}
+	if c.ContentResourceURI == "" {
+		c.ContentResourceURI = client.BaseURL + "/repository/" + string(group.Name)
+	}
+	for _, m := range group.Group.MemberNames {
+		c.Repositories = append(c.Repositories, Repository{ID: m, Name: string(m), ResourceURI: client.BaseURL + "/service/rest/v1/repositories/" + string(m)})
+	}
+	return c
+}
+
+// updatePayload strips the read-only attributes the server returns on a group read.
+func (group nexus3GroupRepo) updatePayload() nexus3GroupRepo {
+	group.Format = ""
+	group.Type = ""
+	group.URL = ""
+	return group
+}
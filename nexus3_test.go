@@ -0,0 +1,485 @@
+package maventools
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const nexus3GroupJSON = `{
+  "name" : "snapshotgroup",
+  "format" : "maven2",
+  "url" : "http://localhost:8081/repository/snapshotgroup",
+  "online" : true,
+  "storage" : {
+    "blobStoreName" : "default",
+    "strictContentTypeValidation" : true
+  },
+  "group" : {
+    "memberNames" : [ "plat.trnk.trnk679" ]
+  },
+  "type" : "group"
+}`
+
+func TestNexus3CreateRepo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("Wanted POST but got %s\n", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/service/rest/v1/repositories/maven/hosted") {
+			t.Fatalf("Wanted URL suffix /service/rest/v1/repositories/maven/hosted but got: %s\n", r.URL.Path)
+		}
+		if r.Header.Get("Content-type") != "application/json" {
+			t.Fatalf("Wanted application/json but got %s for Content-type header", r.Header.Get("Content-type"))
+		}
+
+		authHeader := r.Header.Get("Authorization")
+		if authHeader == "" {
+			t.Fatalf("Wanted an Authorization header but found none")
+		}
+		base64 := authHeader[len("Basic "):]
+		if base64 != "dXNlcjpwYXNzd29yZA==" {
+			t.Fatalf("Wanted dXNlcjpwYXNzd29yZA== but got %s\n", base64)
+		}
+
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("Got an error but was not expecting one: %v\n", err)
+		}
+
+		var repo nexus3HostedRepo
+		if err := json.Unmarshal(b, &repo); err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+		if repo.Name != "somerepo" {
+			t.Fatalf("Want somerepo but got %v\n", repo.Name)
+		}
+		if !repo.Online {
+			t.Fatalf("Want true but got false\n")
+		}
+		if repo.Maven.VersionPolicy != "SNAPSHOT" {
+			t.Fatalf("Want SNAPSHOT but got %v\n", repo.Maven.VersionPolicy)
+		}
+		if repo.Storage.WritePolicy != "ALLOW" {
+			t.Fatalf("Want ALLOW but got %v\n", repo.Storage.WritePolicy)
+		}
+		if repo.Storage.BlobStoreName != "default" {
+			t.Fatalf("Want default but got %v\n", repo.Storage.BlobStoreName)
+		}
+
+		w.WriteHeader(201)
+	}))
+	defer server.Close()
+
+	client := NewNexus3Client(server.URL, "user", "password")
+	i, err := client.CreateSnapshotRepository("somerepo")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if i != 201 {
+		t.Fatalf("Want 201 but got %d\n", i)
+	}
+}
+
+func TestNexus3CreateRepoWithError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(400)
+	}))
+	defer server.Close()
+
+	client := NewNexus3Client(server.URL, "user", "password")
+	i, err := client.CreateSnapshotRepository("somerepo")
+	if err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+	if i != 400 {
+		t.Fatalf("Want 400 but got %d\n", i)
+	}
+}
+
+func TestNexus3RepoExists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("Wanted GET but got %s\n", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/service/rest/v1/repositories/somerepo") {
+			t.Fatalf("Wanted URL suffix /service/rest/v1/repositories/somerepo but got: %s\n", r.URL.Path)
+		}
+		fmt.Fprintf(w, `{"name": "somerepo", "format": "maven2", "type": "hosted"}`)
+	}))
+	defer server.Close()
+
+	client := NewNexus3Client(server.URL, "user", "password")
+	exists, err := client.RepositoryExists("somerepo")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if !exists {
+		t.Fatalf("Wanted true but got false")
+	}
+}
+
+func TestNexus3RepoNotExists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+	}))
+	defer server.Close()
+
+	client := NewNexus3Client(server.URL, "user", "password")
+	exists, err := client.RepositoryExists("somerepo")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if exists {
+		t.Fatalf("Wanted false but got true")
+	}
+}
+
+func TestNexus3DeleteRepo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Fatalf("Wanted DELETE but got %s\n", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/service/rest/v1/repositories/somerepo") {
+			t.Fatalf("Wanted URL suffix /service/rest/v1/repositories/somerepo but got: %s\n", r.URL.Path)
+		}
+		w.WriteHeader(204)
+	}))
+	defer server.Close()
+
+	client := NewNexus3Client(server.URL, "user", "password")
+	rc, err := client.DeleteRepository("somerepo")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 204 {
+		t.Fatalf("Want 204 but got %d\n", rc)
+	}
+}
+
+func TestNexus3RepositoryGroup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("Wanted GET but got %s\n", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/service/rest/v1/repositories/maven/group/snapshotgroup") {
+			t.Fatalf("Wanted URL suffix /service/rest/v1/repositories/maven/group/snapshotgroup but got: %s\n", r.URL.Path)
+		}
+		fmt.Fprintf(w, "%s", nexus3GroupJSON)
+	}))
+	defer server.Close()
+
+	client := NewNexus3Client(server.URL, "user", "password")
+	group, rc, err := client.RepositoryGroup("snapshotgroup")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 200 {
+		t.Fatalf("Want 200 but got %d\n", rc)
+	}
+	if group.ID != "snapshotgroup" {
+		t.Fatalf("Want snapshotgroup but got %v\n", group.ID)
+	}
+	if group.ContentResourceURI != "http://localhost:8081/repository/snapshotgroup" {
+		t.Fatalf("Want http://localhost:8081/repository/snapshotgroup but got %v\n", group.ContentResourceURI)
+	}
+	if len(group.Repositories) != 1 {
+		t.Fatalf("Want 1 but got %d\n", len(group.Repositories))
+	}
+	if group.Repositories[0].ID != "plat.trnk.trnk679" {
+		t.Fatalf("Want plat.trnk.trnk679 but got %v\n", group.Repositories[0].ID)
+	}
+}
+
+func TestNexus3AddToGroup(t *testing.T) {
+	var written []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			if written != nil {
+				fmt.Fprintf(w, "%s", written)
+				return
+			}
+			fmt.Fprintf(w, "%s", nexus3GroupJSON)
+			return
+		}
+		if r.Method != "PUT" {
+			t.Fatalf("Wanted GET or PUT but got %s\n", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/service/rest/v1/repositories/maven/group/snapshotgroup") {
+			t.Fatalf("Wanted URL suffix /service/rest/v1/repositories/maven/group/snapshotgroup but got: %s\n", r.URL.Path)
+		}
+
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+		written = data
+		var group nexus3GroupRepo
+		if err := json.Unmarshal(data, &group); err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+		if group.URL != "" {
+			t.Fatalf("Want empty url but got %v\n", group.URL)
+		}
+		if group.Storage.BlobStoreName != "default" {
+			t.Fatalf("Want default but got %v\n", group.Storage.BlobStoreName)
+		}
+		members := group.Group.MemberNames
+		if len(members) != 2 {
+			t.Fatalf("Want 2 but got %d\n", len(members))
+		}
+		if members[0] != "plat.trnk.trnk679" || members[1] != "somerepo" {
+			t.Fatalf("Want [plat.trnk.trnk679 somerepo] but got %v\n", members)
+		}
+		w.WriteHeader(204)
+	}))
+	defer server.Close()
+
+	client := NewNexus3Client(server.URL, "user", "password")
+	rc, err := client.AddRepositoryToGroup("somerepo", "snapshotgroup")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 204 {
+		t.Fatalf("Want 204 but got %d\n", rc)
+	}
+}
+
+func TestNexus3RemoveFromGroup(t *testing.T) {
+	var written []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			if written != nil {
+				fmt.Fprintf(w, "%s", written)
+				return
+			}
+			fmt.Fprintf(w, "%s", nexus3GroupJSON)
+			return
+		}
+		if r.Method != "PUT" {
+			t.Fatalf("Wanted GET or PUT but got %s\n", r.Method)
+		}
+
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+		written = data
+		var group nexus3GroupRepo
+		if err := json.Unmarshal(data, &group); err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+		if len(group.Group.MemberNames) != 0 {
+			t.Fatalf("Want 0 but got %d\n", len(group.Group.MemberNames))
+		}
+		w.WriteHeader(204)
+	}))
+	defer server.Close()
+
+	client := NewNexus3Client(server.URL, "user", "password")
+	rc, err := client.RemoveRepositoryFromGroup("plat.trnk.trnk679", "snapshotgroup")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 204 {
+		t.Fatalf("Want 204 but got %d\n", rc)
+	}
+}
+
+func TestNexus3CreateReleaseRepo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("Got an error but was not expecting one: %v\n", err)
+		}
+
+		var repo nexus3HostedRepo
+		if err := json.Unmarshal(b, &repo); err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+		if repo.Maven.VersionPolicy != "RELEASE" {
+			t.Fatalf("Want RELEASE but got %v\n", repo.Maven.VersionPolicy)
+		}
+		if repo.Storage.WritePolicy != "ALLOW_ONCE" {
+			t.Fatalf("Want ALLOW_ONCE but got %v\n", repo.Storage.WritePolicy)
+		}
+		w.WriteHeader(201)
+	}))
+	defer server.Close()
+
+	options := NewHostedRepositoryOptions("somerepo", ReleasePolicy)
+	options.WritePolicy = AllowWriteOnce
+
+	client := NewNexus3Client(server.URL, "user", "password")
+	if _, err := client.CreateHostedRepository(options); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+}
+
+func TestNexus3CreateGroup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("Wanted POST but got %s\n", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/service/rest/v1/repositories/maven/group") {
+			t.Fatalf("Wanted URL suffix /service/rest/v1/repositories/maven/group but got: %s\n", r.URL.Path)
+		}
+
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+		var group nexus3GroupRepo
+		if err := json.Unmarshal(data, &group); err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+		if group.Name != "snapshotgroup" {
+			t.Fatalf("Want snapshotgroup but got %v\n", group.Name)
+		}
+		if !group.Online {
+			t.Fatalf("Want true but got false\n")
+		}
+		members := group.Group.MemberNames
+		if len(members) != 2 || members[0] != "releases" || members[1] != "snapshots" {
+			t.Fatalf("Want [releases snapshots] but got %v\n", members)
+		}
+		w.WriteHeader(201)
+	}))
+	defer server.Close()
+
+	client := NewNexus3Client(server.URL, "user", "password")
+	rc, err := client.CreateRepositoryGroup(NewRepositoryGroupOptions("snapshotgroup", "releases", "snapshots"))
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 201 {
+		t.Fatalf("Want 201 but got %d\n", rc)
+	}
+}
+
+const nexus3RepositorySettings = `[
+  {
+    "name" : "maven-releases",
+    "format" : "maven2",
+    "type" : "hosted",
+    "url" : "http://localhost:8081/repository/maven-releases",
+    "maven" : { "versionPolicy" : "RELEASE", "layoutPolicy" : "STRICT" }
+  },
+  {
+    "name" : "plat.trnk.trnk679",
+    "format" : "maven2",
+    "type" : "hosted",
+    "url" : "http://localhost:8081/repository/plat.trnk.trnk679",
+    "maven" : { "versionPolicy" : "SNAPSHOT", "layoutPolicy" : "STRICT" }
+  },
+  {
+    "name" : "npm-proxy",
+    "format" : "npm",
+    "type" : "proxy",
+    "url" : "http://localhost:8081/repository/npm-proxy"
+  },
+  {
+    "name" : "snapshotgroup",
+    "format" : "maven2",
+    "type" : "group",
+    "url" : "http://localhost:8081/repository/snapshotgroup",
+    "group" : { "memberNames" : [ "plat.trnk.trnk679" ] }
+  }
+]`
+
+func TestNexus3ListRepositories(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/service/rest/v1/repositorySettings") {
+			t.Fatalf("Wanted URL suffix /service/rest/v1/repositorySettings but got: %s\n", r.URL.Path)
+		}
+		fmt.Fprintf(w, "%s", nexus3RepositorySettings)
+	}))
+	defer server.Close()
+
+	client := NewNexus3Client(server.URL, "user", "password")
+	repos, _, err := client.ListRepositories(RepositoryFilter{})
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if len(repos) != 2 {
+		t.Fatalf("Want 2 but got %d\n", len(repos))
+	}
+
+	repos, _, err = client.ListRepositories(RepositoryFilter{Policy: SnapshotPolicy})
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if len(repos) != 1 || repos[0].ID != "plat.trnk.trnk679" {
+		t.Fatalf("Want [plat.trnk.trnk679] but got %v\n", repos)
+	}
+	if repos[0].ContentResourceURI != "http://localhost:8081/repository/plat.trnk.trnk679" {
+		t.Fatalf("Want http://localhost:8081/repository/plat.trnk.trnk679 but got %v\n", repos[0].ContentResourceURI)
+	}
+
+	groups, _, err := client.ListRepositoryGroups(RepositoryFilter{})
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if len(groups) != 1 || groups[0].ID != "snapshotgroup" {
+		t.Fatalf("Want [snapshotgroup] but got %v\n", groups)
+	}
+	if len(groups[0].Repositories) != 1 || groups[0].Repositories[0].ID != "plat.trnk.trnk679" {
+		t.Fatalf("Want [plat.trnk.trnk679] but got %v\n", groups[0].Repositories)
+	}
+}
+
+func TestNexus3ChangeGroupMembership(t *testing.T) {
+	var written []byte
+	puts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" && r.URL.Path == "/service/rest/v1/repositorySettings" {
+			fmt.Fprintf(w, `[{"name": "repo1", "format": "maven2", "type": "hosted"}]`)
+			return
+		}
+		if r.Method == "GET" {
+			if written != nil {
+				fmt.Fprintf(w, "%s", written)
+				return
+			}
+			fmt.Fprintf(w, "%s", nexus3GroupJSON)
+			return
+		}
+		if r.Method != "PUT" {
+			t.Fatalf("Wanted GET or PUT but got %s\n", r.Method)
+		}
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+		written = data
+		puts++
+		w.WriteHeader(204)
+	}))
+	defer server.Close()
+
+	client := NewNexus3Client(server.URL, "user", "password")
+	outcomes, rc, err := client.ChangeGroupMembership(MembershipChange{Add: []RepositoryID{"repo1", "repo2"}, Remove: []RepositoryID{"plat.trnk.trnk679"}}, "snapshotgroup")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 204 {
+		t.Fatalf("Want 204 but got %d\n", rc)
+	}
+	if puts != 1 {
+		t.Fatalf("Want 1 PUT but got %d\n", puts)
+	}
+	var group nexus3GroupRepo
+	if err := json.Unmarshal(written, &group); err != nil {
+		t.Fatalf("Not expecting an error but got one: %v\n", err)
+	}
+	if got := fmt.Sprint(group.Group.MemberNames); got != "[repo1]" {
+		t.Fatalf("Want [repo1] but got %s\n", got)
+	}
+	if outcomes["plat.trnk.trnk679"] != MemberRemoved || outcomes["repo1"] != MemberAdded || outcomes["repo2"] != MemberNotFound {
+		t.Fatalf("Want removed, added and not found but got %v\n", outcomes)
+	}
+}
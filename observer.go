@@ -0,0 +1,39 @@
+package maventools
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	// Observer is told about every HTTP exchange a client makes, including each retry, for example to collect metrics.
+	// Set ClientConfig.Observer to install one.  Its methods are called on the goroutine making the request, so they
+	// should return quickly.
+	Observer interface {
+		// BeforeExchange is called before a request is sent.  Only the request fields of e are set.
+		BeforeExchange(ctx context.Context, e Exchange)
+		// AfterExchange is called once the response has been read or the request has failed.
+		AfterExchange(ctx context.Context, e Exchange)
+	}
+
+	// Exchange describes one attempt at an HTTP request made by a client operation.
+	Exchange struct {
+		// Op names the client operation, for example NexusClient.DeleteRepository.
+		Op     string
+		Method string
+		URL    string
+		// Attempt counts from 1; an Attempt above 1 is a retry.
+		Attempt int
+		// RequestBytes is the size of the request body.  A streamed body, such as the content of UploadArtifact, is counted
+		// as it is sent, so RequestBytes is 0 for it in BeforeExchange and the number of bytes sent in AfterExchange.
+		RequestBytes int64
+
+		// StatusCode is 0 if no response was received.
+		StatusCode    int
+		Duration      time.Duration
+		ResponseBytes int64
+		// Err is the error the attempt failed with, or nil.  A response whose status the operation did not expect is
+		// reported as a *StatusError.
+		Err error
+	}
+)
@@ -0,0 +1,49 @@
+package maventools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type recordingObserver struct {
+	before, after []Exchange
+}
+
+func (o *recordingObserver) BeforeExchange(ctx context.Context, e Exchange) {
+	o.before = append(o.before, e)
+}
+
+func (o *recordingObserver) AfterExchange(ctx context.Context, e Exchange) {
+	o.after = append(o.after, e)
+}
+
+func TestObserver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(201)
+		w.Write([]byte("created"))
+	}))
+	defer server.Close()
+
+	observer := &recordingObserver{}
+	client := NewNexusClient(server.URL, "user", "password")
+	client.Observer = observer
+	if _, err := client.CreateSnapshotRepository("somerepo"); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+
+	if len(observer.before) != 1 || len(observer.after) != 1 {
+		t.Fatalf("Want 1 exchange but got %d before and %d after\n", len(observer.before), len(observer.after))
+	}
+	before, after := observer.before[0], observer.after[0]
+	if before.Op != "NexusClient.CreateSnapshotRepository" || before.Method != "POST" || before.URL != server.URL+"/service/local/repositories" {
+		t.Fatalf("Want a POST by NexusClient.CreateSnapshotRepository but got %+v\n", before)
+	}
+	if before.Attempt != 1 || before.RequestBytes == 0 || before.StatusCode != 0 {
+		t.Fatalf("Want the first attempt with a request body and no response but got %+v\n", before)
+	}
+	if after.StatusCode != 201 || after.ResponseBytes != int64(len("created")) || after.Duration <= 0 || after.Err != nil {
+		t.Fatalf("Want a 201 response of 7 bytes but got %+v\n", after)
+	}
+}
@@ -0,0 +1,97 @@
+// Package prommetrics exposes the HTTP traffic of maventools clients as Prometheus metrics.
+//
+//	collector := prommetrics.NewCollector("")
+//	prometheus.MustRegister(collector)
+//	client := maventools.NewNexusClient(url, user, password)
+//	client.Observer = collector
+package prommetrics
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/xoom/maventools"
+)
+
+// Collector is a maventools.Observer that is also a prometheus.Collector.  It records, per client operation, the latency of
+// each HTTP exchange, the exchanges that failed and the retries made.  One Collector may observe any number of clients.
+type Collector struct {
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+	retries  *prometheus.CounterVec
+	inFlight *prometheus.GaugeVec
+}
+
+// NewCollector returns a Collector whose metrics are named namespace_..., or maventools_... if namespace is empty.
+func NewCollector(namespace string) *Collector {
+	if namespace == "" {
+		namespace = "maventools"
+	}
+	return &Collector{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of HTTP exchanges with the repository manager, by client operation, method and response code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"op", "method", "code"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "request_errors_total",
+			Help:      "HTTP exchanges that failed, by client operation and kind of failure: a response code, or network.",
+		}, []string{"op", "kind"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "request_retries_total",
+			Help:      "HTTP exchanges that repeated a failed one, by client operation.",
+		}, []string{"op"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "requests_in_flight",
+			Help:      "HTTP exchanges under way, by client operation.",
+		}, []string{"op"}),
+	}
+}
+
+// BeforeExchange implements maventools.Observer.
+func (c *Collector) BeforeExchange(ctx context.Context, e maventools.Exchange) {
+	c.inFlight.WithLabelValues(e.Op).Inc()
+	if e.Attempt > 1 {
+		c.retries.WithLabelValues(e.Op).Inc()
+	}
+}
+
+// AfterExchange implements maventools.Observer.
+func (c *Collector) AfterExchange(ctx context.Context, e maventools.Exchange) {
+	c.inFlight.WithLabelValues(e.Op).Dec()
+	c.duration.WithLabelValues(e.Op, e.Method, strconv.Itoa(e.StatusCode)).Observe(e.Duration.Seconds())
+	if e.Err != nil {
+		c.errors.WithLabelValues(e.Op, kind(e.Err)).Inc()
+	}
+}
+
+// kind labels a failed exchange with its response code, or with network if it received no response.
+func kind(err error) string {
+	var statusErr *maventools.StatusError
+	if errors.As(err, &statusErr) {
+		return strconv.Itoa(statusErr.StatusCode)
+	}
+	return "network"
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.duration.Describe(ch)
+	c.errors.Describe(ch)
+	c.retries.Describe(ch)
+	c.inFlight.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.duration.Collect(ch)
+	c.errors.Collect(ch)
+	c.retries.Collect(ch)
+	c.inFlight.Collect(ch)
+}
@@ -0,0 +1,78 @@
+package prommetrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/xoom/maventools"
+)
+
+func TestCollector(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(503)
+			return
+		}
+		w.WriteHeader(204)
+	}))
+	defer server.Close()
+
+	collector := NewCollector("")
+	registry := prometheus.NewPedanticRegistry()
+	if err := registry.Register(collector); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+
+	client := maventools.NewNexusClient(server.URL, "user", "password")
+	client.Observer = collector
+	client.RetryPolicy = maventools.NewRetryPolicy()
+	client.RetryPolicy.InitialBackoff = time.Millisecond
+	if _, err := client.DeleteRepository("somerepo"); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+
+	if n := testutil.CollectAndCount(collector, "maventools_request_duration_seconds"); n != 2 {
+		t.Fatalf("Want 2 histograms but got %d\n", n)
+	}
+	want := `
+# HELP maventools_request_errors_total HTTP exchanges that failed, by client operation and kind of failure: a response code, or network.
+# TYPE maventools_request_errors_total counter
+maventools_request_errors_total{kind="503",op="NexusClient.DeleteRepository"} 1
+# HELP maventools_request_retries_total HTTP exchanges that repeated a failed one, by client operation.
+# TYPE maventools_request_retries_total counter
+maventools_request_retries_total{op="NexusClient.DeleteRepository"} 1
+# HELP maventools_requests_in_flight HTTP exchanges under way, by client operation.
+# TYPE maventools_requests_in_flight gauge
+maventools_requests_in_flight{op="NexusClient.DeleteRepository"} 0
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(want), "maventools_request_errors_total", "maventools_request_retries_total", "maventools_requests_in_flight"); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+}
+
+func TestCollectorNetworkError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	collector := NewCollector("nexus")
+	client := maventools.NewNexusClient(server.URL, "user", "password")
+	client.Observer = collector
+	client.RetryPolicy = maventools.RetryPolicy{MaxAttempts: 1}
+	if _, err := client.RepositoryExists("somerepo"); err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+
+	if got := testutil.ToFloat64(collector.errors.WithLabelValues("NexusClient.RepositoryExists", "network")); got != 1 {
+		t.Fatalf("Want 1 but got %v\n", got)
+	}
+	if n := testutil.CollectAndCount(collector, "nexus_request_duration_seconds"); n != 1 {
+		t.Fatalf("Want 1 histogram but got %d\n", n)
+	}
+}
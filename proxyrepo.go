@@ -0,0 +1,237 @@
+package maventools
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+)
+
+type (
+	// ChecksumPolicy determines how a proxy repository treats artifacts whose remote checksums are missing or do not match.
+	ChecksumPolicy string
+
+	// ProxyRepositoryOptions configures a proxy repository created by CreateProxyRepository and describes one read back by
+	// ProxyRepository.  Start from NewProxyRepositoryOptions and adjust from there.
+	ProxyRepositoryOptions struct {
+		ID RepositoryID
+		// Display name.  Defaults to the ID.
+		Name   string
+		Policy RepositoryPolicy
+		// URL of the remote repository being proxied, for example https://repo1.maven.org/maven2/.
+		RemoteURL string
+		// Credentials for the remote repository, if it requires them.  Nexus masks the password when the settings are read back.
+		RemoteUsername string
+		RemotePassword string
+		// Minutes for which cached artifacts, metadata and other items, such as indexes, are considered fresh.  -1 means cached
+		// items never expire.
+		ArtifactMaxAge int
+		MetadataMaxAge int
+		ItemMaxAge     int
+		// Whether Nexus stops contacting the remote repository while it appears to be unreachable.
+		AutoBlock             bool
+		DownloadRemoteIndexes bool
+		ChecksumPolicy        ChecksumPolicy
+		Browseable            bool
+		Indexable             bool
+		Exposed               bool
+		// Minutes for which Nexus remembers that an artifact was not found.
+		NotFoundCacheTTL int
+	}
+
+	// The type posted or retrieved to create or read a proxy repository.
+	proxyrepo struct {
+		XMLName xml.Name      `xml:"repository"`
+		Data    ProxyRepoData `xml:"data"`
+	}
+
+	ProxyRepoData struct {
+		XMLName               xml.Name           `xml:"data"`
+		Id                    RepositoryID       `xml:"id"`
+		Name                  string             `xml:"name"`
+		ContentResourceURI    string             `xml:"contentResourceURI"`
+		Provider              string             `xml:"provider"`
+		ProviderRole          string             `xml:"providerRole"`
+		Format                string             `xml:"format"`
+		RepoType              string             `xml:"repoType"`
+		RepoPolicy            string             `xml:"repoPolicy"`
+		Exposed               bool               `xml:"exposed"`
+		Browseable            bool               `xml:"browseable"`
+		Indexable             bool               `xml:"indexable"`
+		NotFoundCacheTTL      int                `xml:"notFoundCacheTTL"`
+		RemoteStorage         nexusRemoteStorage `xml:"remoteStorage"`
+		DownloadRemoteIndexes bool               `xml:"downloadRemoteIndexes"`
+		ChecksumPolicy        string             `xml:"checksumPolicy"`
+		ArtifactMaxAge        int                `xml:"artifactMaxAge"`
+		MetadataMaxAge        int                `xml:"metadataMaxAge"`
+		ItemMaxAge            int                `xml:"itemMaxAge"`
+		AutoBlockActive       bool               `xml:"autoBlockActive"`
+		FileTypeValidation    bool               `xml:"fileTypeValidation"`
+	}
+
+	nexusRemoteStorage struct {
+		RemoteStorageURL string               `xml:"remoteStorageUrl"`
+		Authentication   *nexusAuthentication `xml:"authentication,omitempty"`
+	}
+
+	nexusAuthentication struct {
+		Username string `xml:"username"`
+		Password string `xml:"password"`
+	}
+)
+
+const (
+	ChecksumIgnore         ChecksumPolicy = "IGNORE"
+	ChecksumWarn           ChecksumPolicy = "WARN"
+	ChecksumStrictIfExists ChecksumPolicy = "STRICT_IF_EXISTS"
+	ChecksumStrict         ChecksumPolicy = "STRICT"
+)
+
+// NewProxyRepositoryOptions returns options for a browseable, indexable and exposed RELEASE proxy of remoteURL named after
+// repositoryID, with Nexus' own defaults for caching, auto-blocking and checksum handling.
+func NewProxyRepositoryOptions(repositoryID RepositoryID, remoteURL string) ProxyRepositoryOptions {
+	return ProxyRepositoryOptions{
+		ID:                    repositoryID,
+		Name:                  string(repositoryID),
+		Policy:                ReleasePolicy,
+		RemoteURL:             remoteURL,
+		ArtifactMaxAge:        -1,
+		MetadataMaxAge:        1440,
+		ItemMaxAge:            1440,
+		AutoBlock:             true,
+		DownloadRemoteIndexes: true,
+		ChecksumPolicy:        ChecksumWarn,
+		Browseable:            true,
+		Indexable:             true,
+		Exposed:               true,
+		NotFoundCacheTTL:      1440,
+	}
+}
+
+// withDefaults validates the options and fills in the defaults for those left empty.
+func (options ProxyRepositoryOptions) withDefaults() (ProxyRepositoryOptions, error) {
+	if options.ID == "" {
+		return options, fmt.Errorf("ProxyRepositoryOptions: repository ID is required")
+	}
+	if options.RemoteURL == "" {
+		return options, fmt.Errorf("ProxyRepositoryOptions: remote URL is required")
+	}
+	switch options.Policy {
+	case ReleasePolicy, SnapshotPolicy, MixedPolicy:
+	default:
+		return options, fmt.Errorf("ProxyRepositoryOptions: unknown repository policy %q", options.Policy)
+	}
+	if options.Name == "" {
+		options.Name = string(options.ID)
+	}
+	switch options.ChecksumPolicy {
+	case "":
+		options.ChecksumPolicy = ChecksumWarn
+	case ChecksumIgnore, ChecksumWarn, ChecksumStrictIfExists, ChecksumStrict:
+	default:
+		return options, fmt.Errorf("ProxyRepositoryOptions: unknown checksum policy %q", options.ChecksumPolicy)
+	}
+	return options, nil
+}
+
+// CreateProxyRepository creates a new Maven2 proxy repository configured by options.  When error is nil, the integer return
+// value is the underlying HTTP response code.
+//
+// CreateProxyRepository uses context.Background internally; to specify the context, use CreateProxyRepositoryContext.
+func (client NexusClient) CreateProxyRepository(options ProxyRepositoryOptions) (int, error) {
+	return client.CreateProxyRepositoryContext(context.Background(), options)
+}
+
+// CreateProxyRepositoryContext is like CreateProxyRepository but uses ctx for its HTTP requests and any pending retries.
+func (client NexusClient) CreateProxyRepositoryContext(ctx context.Context, options ProxyRepositoryOptions) (_ int, err error) {
+	ctx, span := client.startOperation(ctx, "NexusClient.CreateProxyRepository", repositoryAttribute(options.ID))
+	defer func() { span.end(err) }()
+
+	options, err = options.withDefaults()
+	if err != nil {
+		return 0, err
+	}
+
+	repo := proxyrepo{
+		Data: ProxyRepoData{
+			Id:                    options.ID,
+			Name:                  options.Name,
+			Provider:              "maven2",
+			ProviderRole:          "org.sonatype.nexus.proxy.repository.Repository",
+			ContentResourceURI:    client.BaseURL + "/content/repositories/" + string(options.ID),
+			Format:                "maven2",
+			RepoType:              "proxy",
+			RepoPolicy:            string(options.Policy),
+			Exposed:               options.Exposed,
+			Browseable:            options.Browseable,
+			Indexable:             options.Indexable,
+			NotFoundCacheTTL:      options.NotFoundCacheTTL,
+			RemoteStorage:         nexusRemoteStorage{RemoteStorageURL: options.RemoteURL},
+			DownloadRemoteIndexes: options.DownloadRemoteIndexes,
+			ChecksumPolicy:        string(options.ChecksumPolicy),
+			ArtifactMaxAge:        options.ArtifactMaxAge,
+			MetadataMaxAge:        options.MetadataMaxAge,
+			ItemMaxAge:            options.ItemMaxAge,
+			AutoBlockActive:       options.AutoBlock,
+			FileTypeValidation:    true,
+		}}
+	if options.RemoteUsername != "" {
+		repo.Data.RemoteStorage.Authentication = &nexusAuthentication{Username: options.RemoteUsername, Password: options.RemotePassword}
+	}
+
+	data, err := xml.Marshal(&repo)
+	if err != nil {
+		return 0, err
+	}
+
+	rc, _, err := client.send(ctx, request{op: "NexusClient.CreateProxyRepository", method: "POST", path: "/service/local/repositories", body: data, contentType: "application/xml", ok: []int{201}})
+	return rc, err
+}
+
+// ProxyRepository reads back the settings of the proxy repository with the given repositoryID.  It is an error if the
+// repository exists but is not a proxy.
+//
+// ProxyRepository uses context.Background internally; to specify the context, use ProxyRepositoryContext.
+func (client NexusClient) ProxyRepository(repositoryID RepositoryID) (ProxyRepositoryOptions, int, error) {
+	return client.ProxyRepositoryContext(context.Background(), repositoryID)
+}
+
+// ProxyRepositoryContext is like ProxyRepository but uses ctx for its HTTP requests and any pending retries.
+func (client NexusClient) ProxyRepositoryContext(ctx context.Context, repositoryID RepositoryID) (_ ProxyRepositoryOptions, _ int, err error) {
+	ctx, span := client.startOperation(ctx, "NexusClient.ProxyRepository", repositoryAttribute(repositoryID))
+	defer func() { span.end(err) }()
+
+	responseCode, data, err := client.send(ctx, request{op: "NexusClient.ProxyRepository", method: "GET", path: "/service/local/repositories/" + string(repositoryID), accept: "application/xml"})
+	if err != nil {
+		return ProxyRepositoryOptions{}, responseCode, err
+	}
+
+	var repo proxyrepo
+	if err := xml.Unmarshal(data, &repo); err != nil {
+		return ProxyRepositoryOptions{}, 0, err
+	}
+	if repo.Data.RepoType != "proxy" {
+		return ProxyRepositoryOptions{}, responseCode, fmt.Errorf("NexusClient.ProxyRepository(): %v is a %s repository, not a proxy repository\n", repositoryID, repo.Data.RepoType)
+	}
+
+	options := ProxyRepositoryOptions{
+		ID:                    repo.Data.Id,
+		Name:                  repo.Data.Name,
+		Policy:                RepositoryPolicy(repo.Data.RepoPolicy),
+		RemoteURL:             repo.Data.RemoteStorage.RemoteStorageURL,
+		ArtifactMaxAge:        repo.Data.ArtifactMaxAge,
+		MetadataMaxAge:        repo.Data.MetadataMaxAge,
+		ItemMaxAge:            repo.Data.ItemMaxAge,
+		AutoBlock:             repo.Data.AutoBlockActive,
+		DownloadRemoteIndexes: repo.Data.DownloadRemoteIndexes,
+		ChecksumPolicy:        ChecksumPolicy(repo.Data.ChecksumPolicy),
+		Browseable:            repo.Data.Browseable,
+		Indexable:             repo.Data.Indexable,
+		Exposed:               repo.Data.Exposed,
+		NotFoundCacheTTL:      repo.Data.NotFoundCacheTTL,
+	}
+	if auth := repo.Data.RemoteStorage.Authentication; auth != nil {
+		options.RemoteUsername = auth.Username
+		options.RemotePassword = auth.Password
+	}
+	return options, responseCode, nil
+}
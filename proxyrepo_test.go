@@ -0,0 +1,198 @@
+package maventools
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var proxyRepoXML string = `<repository>
+  <data>
+    <id>central</id>
+    <name>Maven Central</name>
+    <provider>maven2</provider>
+    <providerRole>org.sonatype.nexus.proxy.repository.Repository</providerRole>
+    <format>maven2</format>
+    <repoType>proxy</repoType>
+    <repoPolicy>RELEASE</repoPolicy>
+    <exposed>true</exposed>
+    <browseable>true</browseable>
+    <indexable>true</indexable>
+    <notFoundCacheTTL>1440</notFoundCacheTTL>
+    <remoteStorage>
+      <remoteStorageUrl>https://repo1.maven.org/maven2/</remoteStorageUrl>
+      <authentication>
+        <username>mirror</username>
+        <password>|$|N|E|X|U|S|$|</password>
+      </authentication>
+    </remoteStorage>
+    <downloadRemoteIndexes>false</downloadRemoteIndexes>
+    <checksumPolicy>STRICT_IF_EXISTS</checksumPolicy>
+    <artifactMaxAge>-1</artifactMaxAge>
+    <metadataMaxAge>720</metadataMaxAge>
+    <itemMaxAge>360</itemMaxAge>
+    <autoBlockActive>true</autoBlockActive>
+    <fileTypeValidation>true</fileTypeValidation>
+  </data>
+</repository>`
+
+func TestCreateProxyRepo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("Wanted POST but got %s\n", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/service/local/repositories") {
+			t.Fatalf("Wanted URL suffix /service/local/repositories but got: %s\n", r.URL.Path)
+		}
+		if r.Header.Get("Content-type") != "application/xml" {
+			t.Fatalf("Wanted application/xml but got %s for Content-type header", r.Header.Get("Content-type"))
+		}
+
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("Got an error but was not expecting one: %v\n", err)
+		}
+
+		var repo proxyrepo
+		if err := xml.Unmarshal(b, &repo); err != nil {
+			t.Fatalf("Not expecting an error but got one: %v\n", err)
+		}
+		if repo.Data.Id != "central" {
+			t.Fatalf("Want central but got %v\n", repo.Data.Id)
+		}
+		if repo.Data.RepoType != "proxy" {
+			t.Fatalf("Want proxy but got %v\n", repo.Data.RepoType)
+		}
+		if repo.Data.RemoteStorage.RemoteStorageURL != "https://repo1.maven.org/maven2/" {
+			t.Fatalf("Want https://repo1.maven.org/maven2/ but got %v\n", repo.Data.RemoteStorage.RemoteStorageURL)
+		}
+		auth := repo.Data.RemoteStorage.Authentication
+		if auth == nil || auth.Username != "mirror" || auth.Password != "secret" {
+			t.Fatalf("Want mirror/secret credentials but got %+v\n", auth)
+		}
+		if repo.Data.ChecksumPolicy != "STRICT" {
+			t.Fatalf("Want STRICT but got %v\n", repo.Data.ChecksumPolicy)
+		}
+		if repo.Data.ArtifactMaxAge != -1 {
+			t.Fatalf("Want -1 but got %d\n", repo.Data.ArtifactMaxAge)
+		}
+		if repo.Data.MetadataMaxAge != 720 {
+			t.Fatalf("Want 720 but got %d\n", repo.Data.MetadataMaxAge)
+		}
+		if repo.Data.ItemMaxAge != 60 {
+			t.Fatalf("Want 60 but got %d\n", repo.Data.ItemMaxAge)
+		}
+		if repo.Data.AutoBlockActive {
+			t.Fatalf("Want false but got true\n")
+		}
+		if !repo.Data.DownloadRemoteIndexes {
+			t.Fatalf("Want true but got false\n")
+		}
+
+		w.WriteHeader(201)
+	}))
+	defer server.Close()
+
+	options := NewProxyRepositoryOptions("central", "https://repo1.maven.org/maven2/")
+	options.RemoteUsername = "mirror"
+	options.RemotePassword = "secret"
+	options.ChecksumPolicy = ChecksumStrict
+	options.MetadataMaxAge = 720
+	options.ItemMaxAge = 60
+	options.AutoBlock = false
+
+	client := NewNexusClient(server.URL, "user", "password")
+	rc, err := client.CreateProxyRepository(options)
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 201 {
+		t.Fatalf("Want 201 but got %d\n", rc)
+	}
+}
+
+func TestCreateProxyRepoWithoutRemoteURL(t *testing.T) {
+	client := NewNexusClient("http://localhost:0", "user", "password")
+	if _, err := client.CreateProxyRepository(ProxyRepositoryOptions{ID: "central"}); err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+}
+
+func TestCreateProxyRepoRejectsUnknownPolicies(t *testing.T) {
+	client := NewNexusClient("http://localhost:0", "user", "password")
+	options := NewProxyRepositoryOptions("central", "https://repo1.maven.org/maven2/")
+	options.Policy = "RELEASES"
+	if _, err := client.CreateProxyRepository(options); err == nil || !strings.Contains(err.Error(), "RELEASES") {
+		t.Fatalf("Expecting an unknown repository policy error but got %v\n", err)
+	}
+	options = NewProxyRepositoryOptions("central", "https://repo1.maven.org/maven2/")
+	options.ChecksumPolicy = "STRICT_IF_EXIST"
+	if _, err := client.CreateProxyRepository(options); err == nil || !strings.Contains(err.Error(), "STRICT_IF_EXIST") {
+		t.Fatalf("Expecting an unknown checksum policy error but got %v\n", err)
+	}
+}
+
+func TestProxyRepo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("Wanted GET but got %s\n", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/service/local/repositories/central") {
+			t.Fatalf("Wanted URL suffix /service/local/repositories/central but got: %s\n", r.URL.Path)
+		}
+		if r.Header.Get("Accept") != "application/xml" {
+			t.Fatalf("Wanted application/xml but got %s for Accept header", r.Header.Get("Accept"))
+		}
+		fmt.Fprintf(w, "%s", proxyRepoXML)
+	}))
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	options, rc, err := client.ProxyRepository("central")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 200 {
+		t.Fatalf("Want 200 but got %d\n", rc)
+	}
+	if options.Name != "Maven Central" {
+		t.Fatalf("Want Maven Central but got %v\n", options.Name)
+	}
+	if options.RemoteURL != "https://repo1.maven.org/maven2/" {
+		t.Fatalf("Want https://repo1.maven.org/maven2/ but got %v\n", options.RemoteURL)
+	}
+	if options.RemoteUsername != "mirror" {
+		t.Fatalf("Want mirror but got %v\n", options.RemoteUsername)
+	}
+	if options.ChecksumPolicy != ChecksumStrictIfExists {
+		t.Fatalf("Want STRICT_IF_EXISTS but got %v\n", options.ChecksumPolicy)
+	}
+	if options.MetadataMaxAge != 720 {
+		t.Fatalf("Want 720 but got %d\n", options.MetadataMaxAge)
+	}
+	if options.ItemMaxAge != 360 {
+		t.Fatalf("Want 360 but got %d\n", options.ItemMaxAge)
+	}
+	if !options.AutoBlock {
+		t.Fatalf("Want true but got false\n")
+	}
+	if options.DownloadRemoteIndexes {
+		t.Fatalf("Want false but got true\n")
+	}
+}
+
+func TestProxyRepoNotAProxy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s", strings.Replace(proxyRepoXML, "<repoType>proxy</repoType>", "<repoType>hosted</repoType>", 1))
+	}))
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	if _, _, err := client.ProxyRepository("central"); err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+}
@@ -1,6 +1,7 @@
 package maventools
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -54,7 +55,7 @@ func TestGetRepoGroup(t *testing.T) {
 	defer server.Close()
 
 	client := NewNexusClient(server.URL, "user", "password")
-	group, rc, err := client.repositoryGroup("snapshotgroup")
+	group, rc, err := client.repositoryGroup(context.Background(), "NexusClient.RepositoryGroup", "snapshotgroup")
 
 	if err != nil {
 		t.Fatalf("Expecting no error but got one: %v\n", err)
@@ -108,7 +109,7 @@ func TestGetRepoGroupNotFound(t *testing.T) {
 	defer server.Close()
 
 	client := NewNexusClient(server.URL, "user", "password")
-	_, rc, err := client.repositoryGroup("snapshotgroup")
+	_, rc, err := client.repositoryGroup(context.Background(), "NexusClient.RepositoryGroup", "snapshotgroup")
 
 	if err == nil {
 		t.Fatalf("Expecting an error but got none\n")
@@ -0,0 +1,244 @@
+package maventools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// request describes an HTTP exchange made by a client operation.
+type request struct {
+	// op names the client operation, for example NexusClient.DeleteRepository.
+	op     string
+	method string
+	// path is relative to BaseURL.  url, if set, is used in its place as the absolute URL of the request.
+	path string
+	url  string
+	body []byte
+	// stream, if not nil, supplies the body in place of body.  rewind prepares stream for another attempt; without it, the
+	// request is made only once.
+	stream io.Reader
+	rewind func() error
+	// contentType is required with a body.  accept defaults to application/json.
+	contentType string
+	accept      string
+	// sink, if not nil, receives the body of a successful response, which is then not returned.  Once any of it has been
+	// written, the request is not retried.
+	sink io.Writer
+	// header, if not nil, receives the headers of the last response.
+	header *http.Header
+	// ok lists the response codes that count as success; any other is reported as a *StatusError.  Defaults to 200.
+	ok []int
+	// idempotent marks a request that may safely be repeated although its method is not, such as an Artifactory POST that
+	// replaces a repository's configuration.
+	idempotent bool
+}
+
+// isIdempotent reports whether repeating the request has the same effect as making it once.
+func (r request) isIdempotent() bool {
+	switch r.method {
+	case "GET", "HEAD", "PUT", "DELETE", "OPTIONS":
+		return true
+	}
+	return r.idempotent
+}
+
+// repeatable reports whether the body of the request can be sent again.
+func (r request) repeatable() bool {
+	return r.stream == nil || r.rewind != nil
+}
+
+func (r request) succeeded(statusCode int) bool {
+	if len(r.ok) == 0 {
+		return statusCode == http.StatusOK
+	}
+	for _, code := range r.ok {
+		if statusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// send makes the request, retrying it as the client's RetryPolicy allows.  It returns the response code and body of the last
+// attempt.  A response code not listed as ok in r is reported as a *StatusError attributed to r.op.
+func (config ClientConfig) send(ctx context.Context, r request) (int, []byte, error) {
+	policy, err := config.RetryPolicy.withDefaults()
+	if err != nil {
+		return 0, nil, err
+	}
+	if !r.repeatable() {
+		policy.MaxAttempts = 1
+	}
+
+	url := config.BaseURL + r.path
+	if r.url != "" {
+		url = r.url
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, url, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	if r.contentType != "" {
+		req.Header.Add("Content-type", r.contentType)
+	}
+	accept := r.accept
+	if accept == "" {
+		accept = "application/json"
+	}
+	req.Header.Add("Accept", accept)
+
+	logger := config.logger()
+	retry := policy.retrier(ctx, r.isIdempotent())
+	var stream *countingReader
+	if r.stream != nil {
+		stream = &countingReader{r: r.stream}
+		r.stream = stream
+	}
+	var sink *countingWriter
+	if r.sink != nil {
+		sink = &countingWriter{w: r.sink}
+		r.sink = sink
+		retryable := retry.retryable
+		retry.retryable = func(err error) bool {
+			return sink.n == 0 && retryable(err)
+		}
+	}
+	retry.onRetry = func(attempt int, delay time.Duration, err error) {
+		logger.Log(ctx, LevelWarn, "retrying request", Field{FieldOp, r.op}, Field{FieldMethod, r.method}, Field{FieldURL, req.URL.String()},
+			Field{FieldAttempt, attempt + 1}, Field{FieldDelay, delay}, Field{FieldError, err.Error()})
+	}
+	var responseCode int
+	var data []byte
+	attempts := 0
+	try := func() error {
+		attempts++
+		if attempts > 1 && r.stream != nil {
+			if err := r.rewind(); err != nil {
+				return err
+			}
+		}
+		e := Exchange{Op: r.op, Method: r.method, URL: req.URL.String(), Attempt: attempts, RequestBytes: int64(len(r.body))}
+		if config.Observer != nil {
+			config.Observer.BeforeExchange(ctx, e)
+		}
+		attemptCtx, endAttempt := config.startAttempt(ctx, e)
+		start := time.Now()
+		var sent, sunk int64
+		if stream != nil {
+			sent = stream.n
+		}
+		if sink != nil {
+			sunk = sink.n
+		}
+		responseCode, data, e.Err = config.exchange(attemptCtx, req, r)
+		e.StatusCode, e.Duration, e.ResponseBytes = responseCode, time.Since(start), int64(len(data))
+		if stream != nil {
+			e.RequestBytes = stream.n - sent
+		}
+		if sink != nil {
+			e.ResponseBytes += sink.n - sunk
+		}
+		endAttempt(e)
+		if config.Observer != nil {
+			config.Observer.AfterExchange(ctx, e)
+		}
+
+		fields := []Field{{FieldOp, r.op}, {FieldMethod, r.method}, {FieldURL, e.URL}, {FieldStatus, responseCode},
+			{FieldAttempt, attempts}, {FieldDuration, e.Duration}}
+		if e.Err != nil {
+			fields = append(fields, Field{FieldError, e.Err.Error()})
+		}
+		logger.Log(ctx, LevelDebug, "request", fields...)
+		return e.Err
+	}
+	refreshed := false
+	work := func() error {
+		err := try()
+		// Credentials the server rejects are renewed, if they can be, and the request repeated once straight away.
+		if auth, ok := config.Authenticator.(refresher); ok && responseCode == http.StatusUnauthorized && !refreshed && r.repeatable() {
+			refreshed = true
+			auth.invalidate()
+			logger.Log(ctx, LevelInfo, "renewing rejected credentials", Field{FieldOp, r.op})
+			err = try()
+		}
+		return err
+	}
+	return responseCode, data, retry.Try(work)
+}
+
+// exchange makes one attempt at r using a copy of req and returns the response code and body it receives.
+func (config ClientConfig) exchange(ctx context.Context, req *http.Request, r request) (int, []byte, error) {
+	attempt := req.Clone(ctx)
+	if err := config.authenticate(ctx, attempt); err != nil {
+		return 0, nil, err
+	}
+	config.injectTraceContext(ctx, attempt.Header)
+	if r.body != nil {
+		attempt.Body = ioutil.NopCloser(bytes.NewReader(r.body))
+		attempt.ContentLength = int64(len(r.body))
+	} else if r.stream != nil {
+		attempt.Body = ioutil.NopCloser(r.stream)
+	}
+
+	resp, err := config.HttpClient.Do(attempt)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	if r.header != nil {
+		*r.header = resp.Header
+	}
+
+	if r.sink != nil && r.succeeded(resp.StatusCode) {
+		_, err := io.Copy(r.sink, resp.Body)
+		return resp.StatusCode, nil, err
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, err
+	}
+
+	if !r.succeeded(resp.StatusCode) {
+		return resp.StatusCode, data, newStatusError(r.op, resp, data)
+	}
+	return resp.StatusCode, data, nil
+}
+
+// getJSON retrieves path, relative to BaseURL, and decodes the JSON response into v.  The integer return value is the HTTP
+// response code of the last attempt; any status other than 200 is reported as a *StatusError attributed to op.
+func (config ClientConfig) getJSON(ctx context.Context, op, path string, v interface{}) (int, error) {
+	rc, data, err := config.send(ctx, request{op: op, method: "GET", path: path})
+	if err != nil {
+		return rc, err
+	}
+	return rc, json.Unmarshal(data, v)
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
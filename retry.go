@@ -0,0 +1,188 @@
+package maventools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"time"
+)
+
+// RetryPolicy determines how a client retries a request that failed with a transient error.  Fields left zero or nil take
+// the values of NewRetryPolicy(), so the zero RetryPolicy stands for NewRetryPolicy() and a policy that sets only some
+// fields keeps the defaults for the rest.  Defaults that a zero value cannot turn off have a Disable field instead.
+type RetryPolicy struct {
+	// Total number of attempts per request, including the first.  1 disables retries.
+	MaxAttempts int
+	// Delay before the first retry.  The delay doubles before each further retry, up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Fraction, between 0 and 1, by which each delay is randomly lengthened or shortened, so that clients that failed
+	// together do not retry together.
+	Jitter float64
+	// Whether to use each delay exactly as computed, ignoring Jitter.
+	DisableJitter bool
+	// Bound on the time spent on a request, including its retries and the delays between them.  A retry that could not
+	// start within it is not attempted.  A negative MaxElapsed means no bound other than the context's.
+	MaxElapsed time.Duration
+	// HTTP response codes that indicate a transient failure.
+	RetryableStatusCodes []int
+	// Whether to give up on requests that failed without a response, such as on a refused connection or a reset stream,
+	// instead of retrying them.
+	DisableNetworkErrorRetries bool
+	// Whether to retry requests that are not idempotent, such as the POSTs that create repositories and groups.  A POST
+	// that reached the server before failing may have taken effect, and repeating it then fails with ErrAlreadyExists.
+	// Requests that failed to connect never reached the server and are retried regardless.
+	RetryNonIdempotent bool
+}
+
+// retrier runs a unit of work up to maxAttempts times, backing off between failed attempts.  Unlike a plain sleep, the backoff
+// is abandoned as soon as ctx is done, so a cancelled or expired context stops pending retries as well as in-flight requests.
+type retrier struct {
+	ctx         context.Context
+	maxAttempts int
+	backoff     func(attempt int) time.Duration
+	// maxElapsed, when positive, bounds the time from the first attempt to the start of the last.
+	maxElapsed time.Duration
+	// retryable, when not nil, decides whether a failed attempt is worth repeating.  Otherwise every failure is.
+	retryable func(error) bool
+	// onRetry, when not nil, is called before waiting delay to repeat the given failed attempt, counting from 0.
+	onRetry func(attempt int, delay time.Duration, err error)
+}
+
+// NewRetryPolicy returns a policy that makes up to 3 attempts per request, starting the retries after about 100ms and
+// giving up after a minute.  It retries network errors and the response codes 408, 429, 500, 502, 503 and 504, but does not
+// repeat non-idempotent requests that may have reached the server.
+func NewRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:          3,
+		InitialBackoff:       100 * time.Millisecond,
+		MaxBackoff:           5 * time.Second,
+		Jitter:               0.2,
+		MaxElapsed:           time.Minute,
+		RetryableStatusCodes: []int{408, 429, 500, 502, 503, 504},
+	}
+}
+
+// withDefaults fills in the fields of the policy left zero from NewRetryPolicy() and rejects policies that cannot be
+// followed.
+func (policy RetryPolicy) withDefaults() (RetryPolicy, error) {
+	defaults := NewRetryPolicy()
+	if policy.MaxAttempts == 0 {
+		policy.MaxAttempts = defaults.MaxAttempts
+	}
+	if policy.InitialBackoff == 0 {
+		policy.InitialBackoff = defaults.InitialBackoff
+	}
+	if policy.MaxBackoff == 0 {
+		policy.MaxBackoff = defaults.MaxBackoff
+	}
+	if policy.DisableJitter {
+		policy.Jitter = 0
+	} else if policy.Jitter == 0 {
+		policy.Jitter = defaults.Jitter
+	}
+	if policy.MaxElapsed == 0 {
+		policy.MaxElapsed = defaults.MaxElapsed
+	}
+	if policy.RetryableStatusCodes == nil {
+		policy.RetryableStatusCodes = defaults.RetryableStatusCodes
+	}
+	if policy.MaxAttempts < 0 {
+		return policy, fmt.Errorf("RetryPolicy: MaxAttempts must not be negative")
+	}
+	if policy.Jitter < 0 || policy.Jitter > 1 {
+		return policy, fmt.Errorf("RetryPolicy: Jitter must be between 0 and 1")
+	}
+	if policy.InitialBackoff < 0 || policy.MaxBackoff < 0 {
+		return policy, fmt.Errorf("RetryPolicy: backoffs must not be negative")
+	}
+	return policy, nil
+}
+
+// retrier returns a retrier that follows the policy for a request that is idempotent or not.
+func (policy RetryPolicy) retrier(ctx context.Context, idempotent bool) retrier {
+	return retrier{
+		ctx:         ctx,
+		maxAttempts: policy.MaxAttempts,
+		backoff:     policy.backoff,
+		maxElapsed:  policy.MaxElapsed,
+		retryable: func(err error) bool {
+			return policy.retryable(err, idempotent)
+		},
+	}
+}
+
+// backoff returns the jittered delay before the retry that follows the given failed attempt, counting from 0.
+func (policy RetryPolicy) backoff(attempt int) time.Duration {
+	delay := policy.InitialBackoff
+	for i := 0; i < attempt && (policy.MaxBackoff == 0 || delay < policy.MaxBackoff); i++ {
+		delay *= 2
+	}
+	if policy.MaxBackoff > 0 && delay > policy.MaxBackoff {
+		delay = policy.MaxBackoff
+	}
+	if policy.Jitter > 0 {
+		delay += time.Duration(policy.Jitter * (2*rand.Float64() - 1) * float64(delay))
+	}
+	return delay
+}
+
+// retryable reports whether a request that failed with err should be attempted again.  Failures other than a *StatusError
+// come from the network.
+func (policy RetryPolicy) retryable(err error, idempotent bool) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		if !idempotent && !policy.RetryNonIdempotent {
+			return false
+		}
+		for _, code := range policy.RetryableStatusCodes {
+			if statusErr.StatusCode == code {
+				return true
+			}
+		}
+		return false
+	}
+	return !policy.DisableNetworkErrorRetries && (idempotent || policy.RetryNonIdempotent || unsent(err))
+}
+
+// unsent reports whether err shows that a request failed before any of it reached the server.
+func unsent(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// Try calls work until it succeeds, fails with an error not worth retrying, the attempts or the elapsed time are exhausted,
+// or the context is done.  It returns the error of the last attempt, or the context's error if the context ended the retries.
+func (r retrier) Try(work func() error) error {
+	start := time.Now()
+	var err error
+	for attempt := 0; attempt < r.maxAttempts; attempt++ {
+		if err = work(); err == nil {
+			return nil
+		}
+		if ctxErr := r.ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if attempt == r.maxAttempts-1 || (r.retryable != nil && !r.retryable(err)) {
+			break
+		}
+
+		delay := r.backoff(attempt)
+		if r.maxElapsed > 0 && time.Since(start)+delay > r.maxElapsed {
+			break
+		}
+		if r.onRetry != nil {
+			r.onRetry(attempt, delay, err)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-r.ctx.Done():
+			timer.Stop()
+			return r.ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return err
+}
@@ -0,0 +1,315 @@
+package maventools
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testRetrier returns the retrier of a policy that retries every failure up to 3 times, with the given backoff.
+func testRetrier(ctx context.Context, backoff time.Duration) retrier {
+	retry := RetryPolicy{MaxAttempts: 3}.retrier(ctx, true)
+	retry.backoff = func(int) time.Duration { return backoff }
+	return retry
+}
+
+func TestRetrierStopsOnSuccess(t *testing.T) {
+	attempts := 0
+	retry := testRetrier(context.Background(), 0)
+	err := retry.Try(func() error {
+		attempts++
+		if attempts < 2 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("Want 2 but got %d\n", attempts)
+	}
+}
+
+func TestRetrierReturnsLastError(t *testing.T) {
+	attempts := 0
+	retry := testRetrier(context.Background(), 0)
+	err := retry.Try(func() error {
+		attempts++
+		return errors.New("permanent")
+	})
+	if err == nil || err.Error() != "permanent" {
+		t.Fatalf("Want permanent but got %v\n", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("Want 3 but got %d\n", attempts)
+	}
+}
+
+func TestRetrierAbandonsBackoffWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	retry := testRetrier(ctx, time.Hour)
+	err := retry.Try(func() error {
+		attempts++
+		time.AfterFunc(10*time.Millisecond, cancel)
+		return errors.New("transient")
+	})
+	if err != context.Canceled {
+		t.Fatalf("Want context.Canceled but got %v\n", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("Want 1 but got %d\n", attempts)
+	}
+}
+
+func TestRepoExistsContextDeadline(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(500)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	_, err := client.RepositoryExistsContext(ctx, "somerepo")
+	if err != context.DeadlineExceeded {
+		t.Fatalf("Want context.DeadlineExceeded but got %v\n", err)
+	}
+	if requests != 1 {
+		t.Fatalf("Want 1 but got %d\n", requests)
+	}
+}
+
+// quickRetries returns the default policy with delays short enough for tests.
+func quickRetries() RetryPolicy {
+	policy := NewRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+	return policy
+}
+
+// failingServer answers the first failures requests with status and the rest with 201.
+func failingServer(failures, status int) (*httptest.Server, *int) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		w.WriteHeader(201)
+	}))
+	return server, &requests
+}
+
+func TestRetryPolicyRetriesIdempotentRequests(t *testing.T) {
+	server, requests := failingServer(2, 503)
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	client.RetryPolicy = quickRetries()
+	if _, _, err := client.send(context.Background(), request{op: "test", method: "PUT", path: "/", ok: []int{201}}); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if *requests != 3 {
+		t.Fatalf("Want 3 but got %d\n", *requests)
+	}
+}
+
+func TestRetryPolicyDoesNotRetryClientErrors(t *testing.T) {
+	server, requests := failingServer(1, 400)
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	client.RetryPolicy = quickRetries()
+	rc, err := client.RepositoryExists("somerepo")
+	if err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+	if rc {
+		t.Fatalf("Want false but got true\n")
+	}
+	if *requests != 1 {
+		t.Fatalf("Want 1 but got %d\n", *requests)
+	}
+}
+
+func TestRetryPolicyNonIdempotent(t *testing.T) {
+	server, requests := failingServer(1, 503)
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	client.RetryPolicy = quickRetries()
+	rc, err := client.CreateSnapshotRepository("somerepo")
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != 503 {
+		t.Fatalf("Want a 503 StatusError but got %v\n", err)
+	}
+	if rc != 503 {
+		t.Fatalf("Want 503 but got %d\n", rc)
+	}
+	if *requests != 1 {
+		t.Fatalf("Want 1 but got %d\n", *requests)
+	}
+
+	client.RetryPolicy.RetryNonIdempotent = true
+	*requests = 0
+	if rc, err := client.CreateSnapshotRepository("somerepo"); err != nil || rc != 201 {
+		t.Fatalf("Want 201 but got %d, %v\n", rc, err)
+	}
+	if *requests != 2 {
+		t.Fatalf("Want 2 but got %d\n", *requests)
+	}
+}
+
+func TestRetryPolicyRetriesUnsentRequests(t *testing.T) {
+	// Nothing listens on the closed server's address, so every attempt fails to connect.
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	attempts := 0
+	client := NewNexusClient(server.URL, "user", "password")
+	client.RetryPolicy = quickRetries()
+	client.RetryPolicy.RetryableStatusCodes = nil
+	client.HttpClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		attempts++
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+	if _, err := client.CreateSnapshotRepository("somerepo"); err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+	if attempts != 3 {
+		t.Fatalf("Want 3 but got %d\n", attempts)
+	}
+
+	client.RetryPolicy.DisableNetworkErrorRetries = true
+	attempts = 0
+	client.CreateSnapshotRepository("somerepo")
+	if attempts != 1 {
+		t.Fatalf("Want 1 but got %d\n", attempts)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestRetryPolicyMaxAttempts(t *testing.T) {
+	server, requests := failingServer(5, 500)
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	client.RetryPolicy = quickRetries()
+	client.RetryPolicy.MaxAttempts = 1
+	if _, err := client.DeleteRepository("somerepo"); err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+	if *requests != 1 {
+		t.Fatalf("Want 1 but got %d\n", *requests)
+	}
+
+	client.RetryPolicy.MaxAttempts = 4
+	*requests = 0
+	client.DeleteRepository("somerepo")
+	if *requests != 4 {
+		t.Fatalf("Want 4 but got %d\n", *requests)
+	}
+}
+
+func TestRetryPolicyMaxElapsed(t *testing.T) {
+	server, requests := failingServer(5, 500)
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	client.RetryPolicy = quickRetries()
+	client.RetryPolicy.MaxAttempts = 5
+	client.RetryPolicy.InitialBackoff = time.Hour
+	client.RetryPolicy.MaxBackoff = time.Hour
+	client.RetryPolicy.MaxElapsed = time.Second
+	start := time.Now()
+	if _, err := client.DeleteRepository("somerepo"); err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Want the request to give up within a second but it took %v\n", elapsed)
+	}
+	if *requests != 1 {
+		t.Fatalf("Want 1 but got %d\n", *requests)
+	}
+}
+
+func TestRetryPolicyWithDefaults(t *testing.T) {
+	policy, err := RetryPolicy{}.withDefaults()
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if !reflect.DeepEqual(policy, NewRetryPolicy()) {
+		t.Fatalf("Want the default policy but got %+v\n", policy)
+	}
+
+	// A partial policy keeps what it sets and takes the defaults for the rest.
+	policy, err = RetryPolicy{RetryableStatusCodes: []int{409}}.withDefaults()
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if policy.MaxAttempts != 3 || policy.InitialBackoff != 100*time.Millisecond || len(policy.RetryableStatusCodes) != 1 || policy.RetryableStatusCodes[0] != 409 {
+		t.Fatalf("Want 3 attempts retrying 409 but got %+v\n", policy)
+	}
+	policy, err = RetryPolicy{MaxAttempts: 5}.withDefaults()
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if policy.DisableNetworkErrorRetries || policy.Jitter != 0.2 || policy.MaxElapsed != time.Minute {
+		t.Fatalf("Want network error retries, jitter 0.2 and a minute's bound but got %+v\n", policy)
+	}
+
+	// Defaults that a zero value cannot turn off are turned off explicitly.
+	policy, err = RetryPolicy{MaxAttempts: 5, Jitter: 0.5, DisableJitter: true, DisableNetworkErrorRetries: true}.withDefaults()
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if !policy.DisableNetworkErrorRetries || policy.Jitter != 0 {
+		t.Fatalf("Want no network error retries and no jitter but got %+v\n", policy)
+	}
+	if delay := policy.backoff(0); delay != 100*time.Millisecond {
+		t.Fatalf("Want 100ms but got %v\n", delay)
+	}
+
+	for _, policy := range []RetryPolicy{
+		{MaxAttempts: -1},
+		{MaxAttempts: 1, Jitter: 1.5},
+		{MaxAttempts: 1, InitialBackoff: -time.Second},
+	} {
+		if _, err := policy.withDefaults(); err == nil {
+			t.Fatalf("Expecting an error for %+v but did not get one\n", policy)
+		}
+	}
+
+	client := NewNexusClient("http://localhost:1", "user", "password")
+	client.RetryPolicy = RetryPolicy{MaxAttempts: -1}
+	if _, err := client.DeleteRepository("somerepo"); err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Jitter: 0.2}
+	for attempt, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		for i := 0; i < 100; i++ {
+			delay := policy.backoff(attempt)
+			if delay < want*8/10 || delay > want*12/10 {
+				t.Fatalf("Want a delay within 20%% of %v but got %v\n", want, delay)
+			}
+		}
+	}
+}
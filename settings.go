@@ -0,0 +1,307 @@
+package maventools
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type (
+	// Settings holds the parts of a Maven settings.xml file that locate repository managers and the credentials for them.
+	Settings struct {
+		Servers        []SettingsServer  `xml:"servers>server"`
+		Mirrors        []SettingsMirror  `xml:"mirrors>mirror"`
+		Profiles       []SettingsProfile `xml:"profiles>profile"`
+		ActiveProfiles []string          `xml:"activeProfiles>activeProfile"`
+	}
+
+	// SettingsServer holds the credentials Maven uses for the repositories and mirrors with the same ID.
+	SettingsServer struct {
+		ID       string `xml:"id"`
+		Username string `xml:"username"`
+		Password string `xml:"password"`
+	}
+
+	SettingsMirror struct {
+		ID       string `xml:"id"`
+		Name     string `xml:"name,omitempty"`
+		URL      string `xml:"url"`
+		MirrorOf string `xml:"mirrorOf"`
+	}
+
+	SettingsProfile struct {
+		ID                 string               `xml:"id"`
+		Repositories       []SettingsRepository `xml:"repositories>repository"`
+		PluginRepositories []SettingsRepository `xml:"pluginRepositories>pluginRepository"`
+	}
+
+	SettingsRepository struct {
+		ID        string                    `xml:"id"`
+		URL       string                    `xml:"url"`
+		Releases  *SettingsRepositoryPolicy `xml:"releases,omitempty"`
+		Snapshots *SettingsRepositoryPolicy `xml:"snapshots,omitempty"`
+	}
+
+	// SettingsRepositoryPolicy determines whether Maven uses a repository for release or for snapshot versions.
+	SettingsRepositoryPolicy struct {
+		Enabled bool `xml:"enabled"`
+	}
+
+	// The payload of settings-security.xml.
+	settingsSecurity struct {
+		Master     string `xml:"master"`
+		Relocation string `xml:"relocation"`
+	}
+)
+
+// masterPasswordKey is the password with which Maven encrypts the master password in settings-security.xml.
+const masterPasswordKey = "settings.security"
+
+var envReference = regexp.MustCompile(`\$\{env\.([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// LoadSettings reads the Maven settings file at path, or ~/.m2/settings.xml if path is empty.  References of the form
+// ${env.X} are replaced with the value of the environment variable X, and server passwords encrypted with
+// mvn --encrypt-password are decrypted with the master password in the settings security file at securityPath, or
+// ~/.m2/settings-security.xml if securityPath is empty.  The security file need not exist unless a password is encrypted.
+func LoadSettings(path, securityPath string) (Settings, error) {
+	if path == "" || securityPath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return Settings{}, err
+		}
+		if path == "" {
+			path = filepath.Join(home, ".m2", "settings.xml")
+		}
+		if securityPath == "" {
+			securityPath = filepath.Join(home, ".m2", "settings-security.xml")
+		}
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Settings{}, err
+	}
+	var settings Settings
+	if err := xml.Unmarshal(data, &settings); err != nil {
+		return Settings{}, fmt.Errorf("LoadSettings(): %s: %v", path, err)
+	}
+	settings.interpolate()
+
+	var master *string
+	for i, server := range settings.Servers {
+		encrypted, ok := encryptedValue(server.Password)
+		if !ok {
+			continue
+		}
+		if master == nil {
+			m, err := loadMasterPassword(securityPath)
+			if err != nil {
+				return Settings{}, fmt.Errorf("LoadSettings(): password of server %s is encrypted: %v", server.ID, err)
+			}
+			master = &m
+		}
+		password, err := decryptValue(encrypted, *master)
+		if err != nil {
+			return Settings{}, fmt.Errorf("LoadSettings(): password of server %s: %v", server.ID, err)
+		}
+		settings.Servers[i].Password = password
+	}
+	return settings, nil
+}
+
+// interpolate replaces ${env.X} references in the values of the settings.  References to variables that are not set are
+// left as they are, as Maven does.
+func (settings *Settings) interpolate() {
+	expand := func(s *string) {
+		*s = envReference.ReplaceAllStringFunc(*s, func(ref string) string {
+			if value, ok := os.LookupEnv(envReference.FindStringSubmatch(ref)[1]); ok {
+				return value
+			}
+			return ref
+		})
+	}
+	for i := range settings.Servers {
+		expand(&settings.Servers[i].Username)
+		expand(&settings.Servers[i].Password)
+	}
+	for i := range settings.Mirrors {
+		expand(&settings.Mirrors[i].URL)
+	}
+	for i := range settings.Profiles {
+		for j := range settings.Profiles[i].Repositories {
+			expand(&settings.Profiles[i].Repositories[j].URL)
+		}
+		for j := range settings.Profiles[i].PluginRepositories {
+			expand(&settings.Profiles[i].PluginRepositories[j].URL)
+		}
+	}
+}
+
+// Server returns the server with the given id.
+func (settings Settings) Server(id string) (SettingsServer, bool) {
+	for _, server := range settings.Servers {
+		if server.ID == id {
+			return server, true
+		}
+	}
+	return SettingsServer{}, false
+}
+
+// NexusClientForServer returns a client that authenticates with the credentials of the server with the given id.  The
+// Nexus instance is the one serving the mirror, or failing that the profile repository, with the same id.  A Nexus 3
+// repository URL, such as http://host:8081/repository/maven-public, is rejected, as NexusClient speaks the Nexus 2 API.
+func (settings Settings) NexusClientForServer(id string) (NexusClient, error) {
+	server, ok := settings.Server(id)
+	if !ok {
+		return NexusClient{}, fmt.Errorf("NexusClientForServer(): no server %s in settings\n", id)
+	}
+	for _, mirror := range settings.Mirrors {
+		if mirror.ID == id {
+			return newNexusClientForContent("NexusClientForServer", mirror.URL, server)
+		}
+	}
+	for _, profile := range settings.Profiles {
+		for _, repos := range [][]SettingsRepository{profile.Repositories, profile.PluginRepositories} {
+			for _, repo := range repos {
+				if repo.ID == id {
+					return newNexusClientForContent("NexusClientForServer", repo.URL, server)
+				}
+			}
+		}
+	}
+	return NexusClient{}, fmt.Errorf("NexusClientForServer(): no mirror or repository with id %s gives the URL of server %s\n", id, id)
+}
+
+// NexusClientForMirror returns a client for the Nexus instance serving the mirror with the given URL, which authenticates
+// with the credentials of the server whose id matches the mirror's.  As with NexusClientForServer, the mirror must be
+// served by Nexus 2.
+func (settings Settings) NexusClientForMirror(url string) (NexusClient, error) {
+	for _, mirror := range settings.Mirrors {
+		if strings.TrimSuffix(mirror.URL, "/") != strings.TrimSuffix(url, "/") {
+			continue
+		}
+		server, ok := settings.Server(mirror.ID)
+		if !ok {
+			return NexusClient{}, fmt.Errorf("NexusClientForMirror(): no server %s in settings for mirror %s\n", mirror.ID, url)
+		}
+		return newNexusClientForContent("NexusClientForMirror", mirror.URL, server)
+	}
+	return NexusClient{}, fmt.Errorf("NexusClientForMirror(): no mirror with URL %s in settings\n", url)
+}
+
+// newNexusClientForContent returns a client for the Nexus instance that serves the repository content at contentURL.  Any
+// error is attributed to op.
+func newNexusClientForContent(op, contentURL string, server SettingsServer) (NexusClient, error) {
+	baseURL, ok := nexusBaseURL(contentURL)
+	if !ok {
+		return NexusClient{}, fmt.Errorf("%s(): %s is a Nexus 3 repository URL; use NewNexus3Client for Nexus 3\n", op, contentURL)
+	}
+	return NewNexusClient(baseURL, server.Username, server.Password), nil
+}
+
+// nexusBaseURL derives the BaseURL of a Nexus 2 instance from the URL of a repository it serves, such as
+// http://host:8081/nexus/content/groups/public.  A URL without /content/ is taken to be the BaseURL itself.  The boolean
+// return value is false for the URL of a Nexus 3 repository, such as http://host:8081/repository/maven-public.
+func nexusBaseURL(contentURL string) (string, bool) {
+	contentURL = strings.TrimSuffix(contentURL, "/")
+	if i := strings.LastIndex(contentURL, "/content/"); i >= 0 {
+		return contentURL[:i], true
+	}
+	if strings.Contains(contentURL, "/repository/") {
+		return "", false
+	}
+	return contentURL, true
+}
+
+// loadMasterPassword reads and decrypts the master password in the settings security file at path, following a relocation.
+func loadMasterPassword(path string) (string, error) {
+	for relocations := 0; ; relocations++ {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		var security settingsSecurity
+		if err := xml.Unmarshal(data, &security); err != nil {
+			return "", fmt.Errorf("%s: %v", path, err)
+		}
+		if security.Relocation == "" {
+			encrypted, ok := encryptedValue(security.Master)
+			if !ok {
+				return "", fmt.Errorf("%s: no encrypted master password", path)
+			}
+			return decryptValue(encrypted, masterPasswordKey)
+		}
+		if relocations == 5 {
+			return "", fmt.Errorf("%s: too many relocations", path)
+		}
+		path = security.Relocation
+	}
+}
+
+// encryptedValue returns the base64 text between the braces of a value encrypted by Maven, such as {COQLCE6DU6GtcS5P=}.
+// Braces escaped with a backslash, or around text that is not base64, such as an unset ${env.X}, do not count.
+func encryptedValue(value string) (string, bool) {
+	start := unescapedIndex(value, '{', 0)
+	if start < 0 {
+		return "", false
+	}
+	end := unescapedIndex(value, '}', start+1)
+	if end < 0 {
+		return "", false
+	}
+	encrypted := value[start+1 : end]
+	if _, err := base64.StdEncoding.DecodeString(encrypted); err != nil || encrypted == "" {
+		return "", false
+	}
+	return encrypted, true
+}
+
+func unescapedIndex(s string, c byte, from int) int {
+	for i := from; i < len(s); i++ {
+		if s[i] == c && (i == 0 || s[i-1] != '\\') {
+			return i
+		}
+	}
+	return -1
+}
+
+// decryptValue decrypts the base64 text of a value encrypted by Maven with password.  The decoded bytes are an 8-byte
+// salt, a byte giving the length of the trailing padding, and AES-CBC ciphertext whose key and IV are the SHA-256 digest
+// of the password and salt.
+func decryptValue(encrypted, password string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < 9 {
+		return "", fmt.Errorf("encrypted value too short")
+	}
+	salt, padLen := data[:8], int(data[8])
+	if len(data) < 9+padLen || (len(data)-9-padLen)%aes.BlockSize != 0 || len(data)-9-padLen == 0 {
+		return "", fmt.Errorf("malformed encrypted value")
+	}
+	ciphertext := data[9 : len(data)-padLen]
+
+	key := sha256.Sum256(append([]byte(password), salt...))
+	block, err := aes.NewCipher(key[:16])
+	if err != nil {
+		return "", err
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, key[16:]).CryptBlocks(plaintext, ciphertext)
+
+	n := int(plaintext[len(plaintext)-1])
+	if n == 0 || n > aes.BlockSize || !bytes.Equal(plaintext[len(plaintext)-n:], bytes.Repeat([]byte{byte(n)}, n)) {
+		return "", fmt.Errorf("wrong password or corrupt encrypted value")
+	}
+	return string(plaintext[:len(plaintext)-n]), nil
+}
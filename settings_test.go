@@ -0,0 +1,188 @@
+package maventools
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// encryptValue encrypts plaintext with password the way mvn --encrypt-password does, braces included.  The salt, which Maven
+// chooses at random, is derived from plaintext so that the tests are repeatable.
+func encryptValue(t *testing.T, plaintext, password string) string {
+	digest := sha256.Sum256([]byte(plaintext))
+	salt := digest[:8]
+	key := sha256.Sum256(append([]byte(password), salt...))
+	block, err := aes.NewCipher(key[:16])
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	n := aes.BlockSize - len(plaintext)%aes.BlockSize
+	padded := append([]byte(plaintext), bytes.Repeat([]byte{byte(n)}, n)...)
+	ciphertext := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, key[16:]).CryptBlocks(ciphertext, padded)
+
+	padLen := aes.BlockSize - (8+len(ciphertext)+1)%aes.BlockSize
+	data := append(append(append(salt, byte(padLen)), ciphertext...), make([]byte, padLen)...)
+	return "{" + base64.StdEncoding.EncodeToString(data) + "}"
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	return path
+}
+
+const settingsXML = `<?xml version="1.0" encoding="UTF-8"?>
+<settings xmlns="http://maven.apache.org/SETTINGS/1.0.0">
+  <servers>
+    <server>
+      <id>nexus</id>
+      <username>${env.MAVENTOOLS_TEST_USER}</username>
+      <password>%s</password>
+    </server>
+    <server>
+      <id>nexus3</id>
+      <username>deployer</username>
+      <password>${env.MAVENTOOLS_TEST_UNSET}</password>
+    </server>
+  </servers>
+  <mirrors>
+    <mirror>
+      <id>nexus</id>
+      <mirrorOf>*</mirrorOf>
+      <url>http://${env.MAVENTOOLS_TEST_HOST}:8081/nexus/content/groups/public/</url>
+    </mirror>
+  </mirrors>
+  <profiles>
+    <profile>
+      <id>nexus3</id>
+      <repositories>
+        <repository>
+          <id>nexus3</id>
+          <url>https://repo.example.com/repository/maven-releases</url>
+        </repository>
+      </repositories>
+    </profile>
+  </profiles>
+</settings>
+`
+
+func TestLoadSettings(t *testing.T) {
+	t.Setenv("MAVENTOOLS_TEST_USER", "admin")
+	t.Setenv("MAVENTOOLS_TEST_HOST", "nexus.example.com")
+	dir := t.TempDir()
+
+	master := "master secret"
+	securityPath := writeFile(t, dir, "settings-security.xml",
+		"<settingsSecurity><master>"+encryptValue(t, master, "settings.security")+"</master></settingsSecurity>")
+	path := writeFile(t, dir, "settings.xml", strings.Replace(settingsXML, "%s", "comment "+encryptValue(t, "admin123", master)+" trailing", 1))
+
+	settings, err := LoadSettings(path, securityPath)
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	server, ok := settings.Server("nexus")
+	if !ok {
+		t.Fatalf("Want server nexus but did not find it\n")
+	}
+	if server.Username != "admin" || server.Password != "admin123" {
+		t.Fatalf("Want admin/admin123 but got %s/%s\n", server.Username, server.Password)
+	}
+	if server, _ := settings.Server("nexus3"); server.Password != "${env.MAVENTOOLS_TEST_UNSET}" {
+		t.Fatalf("Want the unset reference left alone but got %s\n", server.Password)
+	}
+
+	client, err := settings.NexusClientForMirror("http://nexus.example.com:8081/nexus/content/groups/public")
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if client.BaseURL != "http://nexus.example.com:8081/nexus" || client.Username != "admin" || client.Password != "admin123" {
+		t.Fatalf("Want admin@http://nexus.example.com:8081/nexus but got %s@%s\n", client.Username, client.BaseURL)
+	}
+
+	// The nexus3 repository is served by Nexus 3, which NexusClient does not speak.
+	if _, err := settings.NexusClientForServer("nexus3"); err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+	if _, err := settings.NexusClientForServer("central"); err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+	if _, err := settings.NexusClientForMirror("http://elsewhere/"); err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+}
+
+func TestLoadSettingsRelocatedMaster(t *testing.T) {
+	dir := t.TempDir()
+	master := "relocated"
+	relocated := writeFile(t, dir, "relocated.xml",
+		"<settingsSecurity><master>"+encryptValue(t, master, "settings.security")+"</master></settingsSecurity>")
+	securityPath := writeFile(t, dir, "settings-security.xml", "<settingsSecurity><relocation>"+relocated+"</relocation></settingsSecurity>")
+	path := writeFile(t, dir, "settings.xml", strings.Replace(settingsXML, "%s", encryptValue(t, "s3cr3t", master), 1))
+
+	settings, err := LoadSettings(path, securityPath)
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if server, _ := settings.Server("nexus"); server.Password != "s3cr3t" {
+		t.Fatalf("Want s3cr3t but got %s\n", server.Password)
+	}
+}
+
+func TestLoadSettingsErrors(t *testing.T) {
+	dir := t.TempDir()
+	encrypted := encryptValue(t, "admin123", "master")
+	path := writeFile(t, dir, "settings.xml", strings.Replace(settingsXML, "%s", encrypted, 1))
+
+	// An encrypted password needs the security file.
+	if _, err := LoadSettings(path, filepath.Join(dir, "missing.xml")); err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+
+	// The wrong master password is detected.
+	securityPath := writeFile(t, dir, "settings-security.xml",
+		"<settingsSecurity><master>"+encryptValue(t, "other", "settings.security")+"</master></settingsSecurity>")
+	if _, err := LoadSettings(path, securityPath); err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+
+	// Plain passwords, and braces escaped with a backslash, need no security file.
+	path = writeFile(t, dir, "plain.xml", strings.Replace(settingsXML, "%s", `\{not encrypted\}`, 1))
+	settings, err := LoadSettings(path, filepath.Join(dir, "missing.xml"))
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if server, _ := settings.Server("nexus"); server.Password != `\{not encrypted\}` {
+		t.Fatalf("Want the password unchanged but got %s\n", server.Password)
+	}
+
+	if _, err := LoadSettings(filepath.Join(dir, "nosettings.xml"), ""); err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+}
+
+func TestNexusBaseURL(t *testing.T) {
+	var tests = []struct {
+		url, want string
+	}{
+		{"http://localhost:8081/nexus/content/groups/public/", "http://localhost:8081/nexus"},
+		{"http://localhost:8081/nexus/content/repositories/releases", "http://localhost:8081/nexus"},
+		{"https://repo.example.com/nexus", "https://repo.example.com/nexus"},
+	}
+	for _, test := range tests {
+		if got, ok := nexusBaseURL(test.url); !ok || got != test.want {
+			t.Fatalf("Want %s but got %s\n", test.want, got)
+		}
+	}
+	if got, ok := nexusBaseURL("https://repo.example.com/repository/maven-public/"); ok {
+		t.Fatalf("Want a Nexus 3 URL rejected but got %s\n", got)
+	}
+}
@@ -0,0 +1,112 @@
+package maventools
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the spans the package records.
+const instrumentationName = "github.com/xoom/maventools"
+
+// Attributes of the spans the package records.  The spans of HTTP requests also carry the standard HTTP attributes.
+const (
+	AttributeOp             = attribute.Key("maventools.op")
+	AttributeRepositoryID   = attribute.Key("maventools.repository.id")
+	AttributeRepositoryIDs  = attribute.Key("maventools.repository.ids")
+	AttributeGroupID        = attribute.Key("maventools.group.id")
+	AttributeMembersAdded   = attribute.Key("maventools.membership.add")
+	AttributeMembersRemoved = attribute.Key("maventools.membership.remove")
+	AttributeCoordinate     = attribute.Key("maventools.coordinate")
+)
+
+// operationSpan is the span of a client operation, or nil if tracing is off.
+type operationSpan struct {
+	span trace.Span
+}
+
+// startOperation starts the span of the client operation op, whose HTTP requests then record child spans.  It does nothing
+// unless the client has a TracerProvider.
+func (config ClientConfig) startOperation(ctx context.Context, op string, attrs ...attribute.KeyValue) (context.Context, operationSpan) {
+	if config.TracerProvider == nil {
+		return ctx, operationSpan{}
+	}
+	attrs = append(attrs, AttributeOp.String(op))
+	ctx, span := config.TracerProvider.Tracer(instrumentationName).Start(ctx, op, trace.WithAttributes(attrs...))
+	return ctx, operationSpan{span: span}
+}
+
+// end ends the span, marking it failed if err is not nil.
+func (s operationSpan) end(err error) {
+	if s.span == nil {
+		return
+	}
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
+
+// startAttempt starts the span of the attempt at an HTTP request described by e.  The returned function ends the span once
+// the attempt is over.  It does nothing unless the client has a TracerProvider.
+func (config ClientConfig) startAttempt(ctx context.Context, e Exchange) (context.Context, func(Exchange)) {
+	if config.TracerProvider == nil {
+		return ctx, func(Exchange) {}
+	}
+	ctx, span := config.TracerProvider.Tracer(instrumentationName).Start(ctx, "HTTP "+e.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			AttributeOp.String(e.Op),
+			attribute.String("http.request.method", e.Method),
+			attribute.String("url.full", e.URL),
+			attribute.Int("http.request.resend_count", e.Attempt-1),
+		))
+	return ctx, func(e Exchange) {
+		if e.StatusCode != 0 {
+			span.SetAttributes(attribute.Int("http.response.status_code", e.StatusCode))
+		}
+		if e.Err != nil {
+			span.RecordError(e.Err)
+			span.SetStatus(codes.Error, e.Err.Error())
+		}
+		span.End()
+	}
+}
+
+// injectTraceContext adds the trace context of ctx to the headers of a request, so that the server can join the trace.  It
+// does nothing unless the client has a TracerProvider.
+func (config ClientConfig) injectTraceContext(ctx context.Context, header http.Header) {
+	if config.TracerProvider == nil {
+		return
+	}
+	propagator := config.Propagator
+	if propagator == nil {
+		propagator = propagation.TraceContext{}
+	}
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+func repositoryAttribute(repositoryID RepositoryID) attribute.KeyValue {
+	return AttributeRepositoryID.String(string(repositoryID))
+}
+
+func groupAttribute(groupID GroupID) attribute.KeyValue {
+	return AttributeGroupID.String(string(groupID))
+}
+
+func coordinateAttribute(c Coordinate) attribute.KeyValue {
+	return AttributeCoordinate.String(c.String())
+}
+
+func repositoriesAttribute(key attribute.Key, repositoryIDs []RepositoryID) attribute.KeyValue {
+	ids := make([]string, len(repositoryIDs))
+	for i, id := range repositoryIDs {
+		ids[i] = string(id)
+	}
+	return key.StringSlice(ids)
+}
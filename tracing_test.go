@@ -0,0 +1,170 @@
+package maventools
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTracedClient(url string) (NexusClient, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	client := NewNexusClient(url, "user", "password")
+	client.TracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	return client, exporter
+}
+
+func attributeOf(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTracingAddToGroup(t *testing.T) {
+	_, server := newGroupServer(t, "a")
+	defer server.Close()
+	var traceparents []string
+	handler := server.Config.Handler
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		handler.ServeHTTP(w, r)
+	})
+
+	client, exporter := newTracedClient(server.URL)
+	if _, err := client.AddRepositoryToGroup("somerepo", "snapshotgroup"); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+
+	// The spans of the GET, the PUT and the GET that verifies it end before that of the operation.
+	spans := exporter.GetSpans()
+	if len(spans) != 4 {
+		t.Fatalf("Want 4 spans but got %d\n", len(spans))
+	}
+	requests, op := spans[:3], spans[3]
+	if op.Name != "NexusClient.AddRepositoryToGroup" {
+		t.Fatalf("Want NexusClient.AddRepositoryToGroup but got %s\n", op.Name)
+	}
+	for i, method := range []string{"GET", "PUT", "GET"} {
+		if requests[i].Name != "HTTP "+method {
+			t.Fatalf("Want HTTP %s but got %s\n", method, requests[i].Name)
+		}
+	}
+	if op.Parent.IsValid() {
+		t.Fatalf("Want the operation span to be a root span\n")
+	}
+	for _, child := range requests {
+		if child.Parent.SpanID() != op.SpanContext.SpanID() {
+			t.Fatalf("Want %s to be a child of the operation span\n", child.Name)
+		}
+		if child.SpanKind != trace.SpanKindClient {
+			t.Fatalf("Want a client span but got %v\n", child.SpanKind)
+		}
+		if got := attributeOf(child, "http.response.status_code").AsInt64(); got != 200 {
+			t.Fatalf("Want 200 but got %d\n", got)
+		}
+	}
+	if got := attributeOf(op, AttributeRepositoryID).AsString(); got != "somerepo" {
+		t.Fatalf("Want somerepo but got %s\n", got)
+	}
+	if got := attributeOf(op, AttributeGroupID).AsString(); got != "snapshotgroup" {
+		t.Fatalf("Want snapshotgroup but got %s\n", got)
+	}
+
+	if len(traceparents) != 3 {
+		t.Fatalf("Want 3 requests but got %d\n", len(traceparents))
+	}
+	for i, child := range requests {
+		want := "00-" + op.SpanContext.TraceID().String() + "-" + child.SpanContext.SpanID().String() + "-01"
+		if traceparents[i] != want {
+			t.Fatalf("Want traceparent %s but got %s\n", want, traceparents[i])
+		}
+	}
+}
+
+func TestTracingRetryAndError(t *testing.T) {
+	server, requests := failingServer(5, 503)
+	defer server.Close()
+
+	client, exporter := newTracedClient(server.URL)
+	client.RetryPolicy = quickRetries()
+	_, err := client.DeleteRepository("somerepo")
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("Want a StatusError but got %v\n", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != *requests+1 {
+		t.Fatalf("Want %d spans but got %d\n", *requests+1, len(spans))
+	}
+	for i, attempt := range spans[:*requests] {
+		if got := attributeOf(attempt, "http.request.resend_count").AsInt64(); got != int64(i) {
+			t.Fatalf("Want resend count %d but got %d\n", i, got)
+		}
+		if attempt.Status.Code != codes.Error {
+			t.Fatalf("Want an error status but got %v\n", attempt.Status.Code)
+		}
+	}
+	op := spans[len(spans)-1]
+	if op.Name != "NexusClient.DeleteRepository" || op.Status.Code != codes.Error || op.Status.Description != err.Error() {
+		t.Fatalf("Want a failed NexusClient.DeleteRepository span but got %s with %+v\n", op.Name, op.Status)
+	}
+}
+
+func TestTracingOneSpanPerOperation(t *testing.T) {
+	gs, server := newGroupServer(t, "a")
+	defer server.Close()
+	gs.repositories = append(gs.repositories, "somerepo")
+
+	client, exporter := newTracedClient(server.URL)
+	if _, _, err := client.AddRepositoriesToGroup([]RepositoryID{"somerepo"}, "snapshotgroup"); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	created, _ := failingServer(0, 0)
+	defer created.Close()
+	other := NewNexusClient(created.URL, "user", "password")
+	other.TracerProvider = client.TracerProvider
+	if _, err := other.CreateSnapshotRepository("somerepo"); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+
+	var ops []string
+	for _, span := range exporter.GetSpans() {
+		if !span.Parent.IsValid() {
+			ops = append(ops, span.Name)
+		} else if span.SpanKind != trace.SpanKindClient {
+			t.Fatalf("Want only request spans below an operation but got %s\n", span.Name)
+		}
+	}
+	if len(ops) != 2 || ops[0] != "NexusClient.AddRepositoriesToGroup" || ops[1] != "NexusClient.CreateSnapshotRepository" {
+		t.Fatalf("Want NexusClient.AddRepositoriesToGroup and NexusClient.CreateSnapshotRepository but got %v\n", ops)
+	}
+}
+
+func TestTracingOff(t *testing.T) {
+	server, _ := failingServer(0, 0)
+	defer server.Close()
+
+	var traceparent string
+	handler := server.Config.Handler
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		handler.ServeHTTP(w, r)
+	})
+
+	client := NewNexusClient(server.URL, "user", "password")
+	if _, err := client.CreateSnapshotRepository("somerepo"); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if traceparent != "" {
+		t.Fatalf("Want no traceparent but got %s\n", traceparent)
+	}
+}
@@ -0,0 +1,196 @@
+package maventools
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingServer stores the body of each PUT under its path and serves it back to GETs, answering 404 for paths never
+// written.  failPuts, when positive, answers that many PUTs of content with 503 first.
+type recordingServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	files    map[string][]byte
+	written  []string
+	failPuts int
+}
+
+func newRecordingServer() *recordingServer {
+	s := &recordingServer{files: make(map[string][]byte)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch r.Method {
+		case "GET":
+			data, ok := s.files[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(data)
+		case "PUT":
+			data, _ := ioutil.ReadAll(r.Body)
+			if s.failPuts > 0 {
+				s.failPuts--
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			s.files[r.URL.Path] = data
+			s.written = append(s.written, r.URL.Path)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	return s
+}
+
+func TestUploadArtifact(t *testing.T) {
+	server := newRecordingServer()
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	c := Coordinate{GroupID: "org.example", ArtifactID: "lib", Version: "1.0", Extension: "jar"}
+	rc, err := client.UploadArtifact("releases", c, strings.NewReader("jar content"))
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if rc != 201 {
+		t.Fatalf("Want 201 but got %d\n", rc)
+	}
+
+	file := "/content/repositories/releases/org/example/lib/1.0/lib-1.0.jar"
+	metadata := "/content/repositories/releases/org/example/lib/maven-metadata.xml"
+	want := []string{
+		file, file + ".md5", file + ".sha1", file + ".sha256", file + ".sha512",
+		metadata, metadata + ".md5", metadata + ".sha1", metadata + ".sha256", metadata + ".sha512",
+	}
+	if strings.Join(server.written, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("Want %v but got %v\n", want, server.written)
+	}
+	if string(server.files[file]) != "jar content" {
+		t.Fatalf("Want jar content but got %s\n", server.files[file])
+	}
+	sum := sha1.Sum([]byte("jar content"))
+	if got := string(server.files[file+".sha1"]); got != hex.EncodeToString(sum[:]) {
+		t.Fatalf("Want %x but got %s\n", sum, got)
+	}
+	sum = sha1.Sum(server.files[metadata])
+	if got := string(server.files[metadata+".sha1"]); got != hex.EncodeToString(sum[:]) {
+		t.Fatalf("Want %x but got %s\n", sum, got)
+	}
+
+	m, err := ParseMetadata(server.files[metadata])
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if m.GroupID != "org.example" || m.ArtifactID != "lib" || m.Versioning == nil {
+		t.Fatalf("Want versioned metadata of org.example:lib but got %+v\n", m)
+	}
+	if m.Versioning.Latest != "1.0" || m.Versioning.Release != "1.0" || len(m.Versioning.Versions) != 1 || len(m.Versioning.LastUpdated) != 14 {
+		t.Fatalf("Want 1.0 released but got %+v\n", *m.Versioning)
+	}
+}
+
+func TestUploadArtifactUpdatesExistingMetadata(t *testing.T) {
+	server := newRecordingServer()
+	defer server.Close()
+
+	client := NewNexus3Client(server.URL, "user", "password")
+	for _, version := range []string{"1.0", "1.1-SNAPSHOT", "1.0"} {
+		c := Coordinate{GroupID: "org.example", ArtifactID: "lib", Version: version}
+		if _, err := client.UploadArtifact("maven-releases", c, strings.NewReader(version)); err != nil {
+			t.Fatalf("Expecting no error but got one: %v\n", err)
+		}
+	}
+
+	m, err := ParseMetadata(server.files["/repository/maven-releases/org/example/lib/maven-metadata.xml"])
+	if err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	if strings.Join(m.Versioning.Versions, ",") != "1.0,1.1-SNAPSHOT" {
+		t.Fatalf("Want 1.0,1.1-SNAPSHOT but got %v\n", m.Versioning.Versions)
+	}
+	if m.Versioning.Latest != "1.0" || m.Versioning.Release != "1.0" {
+		t.Fatalf("Want latest and release 1.0 but got %+v\n", *m.Versioning)
+	}
+}
+
+func TestUploadArtifactRetriesSeekableContent(t *testing.T) {
+	server := newRecordingServer()
+	server.failPuts = 1
+	defer server.Close()
+
+	client := NewArtifactoryClient(server.URL, "user", "password")
+	client.RetryPolicy = quickRetries()
+	c := Coordinate{GroupID: "org.example", ArtifactID: "lib", Version: "1.0", Classifier: "sources"}
+	if _, err := client.UploadArtifact("libs-release-local", c, bytes.NewReader([]byte("sources"))); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	file := "/libs-release-local/org/example/lib/1.0/lib-1.0-sources.jar"
+	sum := sha1.Sum([]byte("sources"))
+	if string(server.files[file]) != "sources" || string(server.files[file+".sha1"]) != hex.EncodeToString(sum[:]) {
+		t.Fatalf("Want sources with checksum %x but got %s with %s\n", sum, server.files[file], server.files[file+".sha1"])
+	}
+}
+
+func TestUploadArtifactDoesNotRetryStreams(t *testing.T) {
+	server := newRecordingServer()
+	server.failPuts = 1
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	client.RetryPolicy = quickRetries()
+	c := Coordinate{GroupID: "org.example", ArtifactID: "lib", Version: "1.0"}
+	rc, err := client.UploadArtifact("releases", c, ioutil.NopCloser(strings.NewReader("jar content")))
+	if err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+	if rc != 503 {
+		t.Fatalf("Want 503 but got %d\n", rc)
+	}
+	if len(server.written) != 0 {
+		t.Fatalf("Want nothing written but got %v\n", server.written)
+	}
+}
+
+func TestUploadArtifactRejectsInvalidCoordinates(t *testing.T) {
+	server := newRecordingServer()
+	defer server.Close()
+
+	client := NewNexusClient(server.URL, "user", "password")
+	c := Coordinate{GroupID: "org.example", ArtifactID: "lib", Version: "../1.0"}
+	if _, err := client.UploadArtifact("releases", c, strings.NewReader("")); err == nil {
+		t.Fatalf("Expecting an error but did not get one\n")
+	}
+	if len(server.written) != 0 {
+		t.Fatalf("Want nothing written but got %v\n", server.written)
+	}
+}
+
+func TestUploadArtifactReportsStreamedBytes(t *testing.T) {
+	server := newRecordingServer()
+	server.failPuts = 1
+	defer server.Close()
+
+	observer := &recordingObserver{}
+	client := NewNexusClient(server.URL, "user", "password")
+	client.RetryPolicy = quickRetries()
+	client.Observer = observer
+	c := Coordinate{GroupID: "org.example", ArtifactID: "lib", Version: "1.0"}
+	if _, err := client.UploadArtifact("releases", c, strings.NewReader("jar content")); err != nil {
+		t.Fatalf("Expecting no error but got one: %v\n", err)
+	}
+	for _, e := range observer.after[:2] {
+		if e.Method != "PUT" || e.RequestBytes != int64(len("jar content")) {
+			t.Fatalf("Want a PUT of 11 bytes but got %+v\n", e)
+		}
+	}
+}
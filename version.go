@@ -0,0 +1,406 @@
+package maventools
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version is a Maven version, ordered as Maven's ComparableVersion orders versions.  A version is split into numbers and
+// qualifiers at dots, hyphens and changes between digits and letters.  Numbers compare numerically and rank above
+// qualifiers.  The qualifiers alpha (a), beta (b), milestone (m), rc (cr) and snapshot rank in that order below a release,
+// which an absent qualifier or ga, final or release marks, and sp ranks above it, followed by any other qualifier in
+// alphabetical order.  Trailing zeros and release qualifiers are insignificant, so 1, 1.0 and 1.0-final are equal.
+type Version struct {
+	s     string
+	items *versionItems
+}
+
+// versionItem is a part of a parsed Version: a versionInt, a versionQualifier or a *versionItems.  A nil versionItem stands
+// for a part missing from the shorter of two versions being compared.
+type versionItem interface {
+	compare(other versionItem) int
+	isNull() bool
+}
+
+type (
+	// versionInt is a number, its digits without leading zeros.
+	versionInt string
+	// versionQualifier is a qualifier in lower case with its aliases resolved.
+	versionQualifier string
+	// versionItems holds the parts of a version, or of the rest of it after a hyphen or a change between digits and letters.
+	versionItems struct {
+		items []versionItem
+	}
+)
+
+var (
+	// versionQualifiers lists the well-known qualifiers from lowest to highest.  The empty qualifier marks a release.
+	versionQualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+	versionAliases    = map[string]string{"ga": "", "final": "", "release": "", "cr": "rc"}
+	// releaseQualifier is the comparable form of the empty qualifier.
+	releaseQualifier = versionQualifier("").comparable()
+)
+
+// ParseVersion parses a Maven version.  As in Maven, any string is a version.
+func ParseVersion(s string) Version {
+	v := strings.ToLower(s)
+	root := &versionItems{}
+	list := root
+	stack := []*versionItems{root}
+	nest := func() {
+		l := &versionItems{}
+		list.items = append(list.items, l)
+		list, stack = l, append(stack, l)
+	}
+
+	digits := false
+	start := 0
+	for i := 0; i < len(v); i++ {
+		switch c := v[i]; {
+		case c == '.' || c == '-':
+			if i == start {
+				list.items = append(list.items, versionInt("0"))
+			} else {
+				list.items = append(list.items, parseVersionItem(digits, v[start:i]))
+			}
+			start = i + 1
+			if c == '-' {
+				nest()
+			}
+		case '0' <= c && c <= '9':
+			if !digits && i > start {
+				list.items = append(list.items, newVersionQualifier(v[start:i], true))
+				start = i
+				nest()
+			}
+			digits = true
+		default:
+			if digits && i > start {
+				list.items = append(list.items, parseVersionItem(true, v[start:i]))
+				start = i
+				nest()
+			}
+			digits = false
+		}
+	}
+	if len(v) > start {
+		list.items = append(list.items, parseVersionItem(digits, v[start:]))
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		stack[i].normalize()
+	}
+	return Version{s: s, items: root}
+}
+
+func parseVersionItem(digits bool, s string) versionItem {
+	if digits {
+		if s = strings.TrimLeft(s, "0"); s == "" {
+			s = "0"
+		}
+		return versionInt(s)
+	}
+	return newVersionQualifier(s, false)
+}
+
+// newVersionQualifier resolves the aliases of qualifier s.  A single a, b or m followed by a number abbreviates alpha, beta
+// or milestone.
+func newVersionQualifier(s string, followedByDigit bool) versionQualifier {
+	if followedByDigit && len(s) == 1 {
+		switch s {
+		case "a":
+			s = "alpha"
+		case "b":
+			s = "beta"
+		case "m":
+			s = "milestone"
+		}
+	}
+	if alias, ok := versionAliases[s]; ok {
+		s = alias
+	}
+	return versionQualifier(s)
+}
+
+// String returns the version as it was parsed.
+func (v Version) String() string {
+	return v.s
+}
+
+// Compare returns -1, 0 or +1 as v orders before, the same as or after w.
+func (v Version) Compare(w Version) int {
+	return v.list().compare(w.list())
+}
+
+// IsSnapshot reports whether the version is a snapshot, such as 1.0-SNAPSHOT or 1.0-20240102.030405-6.
+func (v Version) IsSnapshot() bool {
+	return Coordinate{Version: v.s}.IsSnapshot()
+}
+
+func (v Version) list() *versionItems {
+	if v.items == nil {
+		return &versionItems{}
+	}
+	return v.items
+}
+
+func (i versionInt) compare(other versionItem) int {
+	switch other := other.(type) {
+	case nil:
+		if i.isNull() {
+			return 0
+		}
+		return 1
+	case versionInt:
+		switch {
+		case len(i) < len(other):
+			return -1
+		case len(i) > len(other):
+			return 1
+		}
+		return strings.Compare(string(i), string(other))
+	}
+	// 1.1 > 1-sp and 1.1 > 1-1
+	return 1
+}
+
+func (i versionInt) isNull() bool {
+	return i == "0"
+}
+
+// comparable returns a key that orders the well-known qualifiers by rank, followed by the others alphabetically.
+func (q versionQualifier) comparable() string {
+	for i, known := range versionQualifiers {
+		if string(q) == known {
+			return strconv.Itoa(i)
+		}
+	}
+	return strconv.Itoa(len(versionQualifiers)) + "-" + string(q)
+}
+
+func (q versionQualifier) compare(other versionItem) int {
+	switch other := other.(type) {
+	case nil:
+		// 1-rc < 1, 1-sp > 1
+		return strings.Compare(q.comparable(), releaseQualifier)
+	case versionQualifier:
+		return strings.Compare(q.comparable(), other.comparable())
+	}
+	// 1-rc < 1.1 and 1-rc < 1-1
+	return -1
+}
+
+func (q versionQualifier) isNull() bool {
+	return q.comparable() == releaseQualifier
+}
+
+func (l *versionItems) compare(other versionItem) int {
+	switch other := other.(type) {
+	case nil:
+		for _, item := range l.items {
+			if result := item.compare(nil); result != 0 {
+				return result
+			}
+		}
+		return 0
+	case versionInt:
+		// 1-1 < 1.1
+		return -1
+	case versionQualifier:
+		// 1-1 > 1-sp
+		return 1
+	case *versionItems:
+		for i := 0; i < len(l.items) || i < len(other.items); i++ {
+			var left, right versionItem
+			if i < len(l.items) {
+				left = l.items[i]
+			}
+			if i < len(other.items) {
+				right = other.items[i]
+			}
+			var result int
+			if left == nil {
+				result = -right.compare(nil)
+			} else {
+				result = left.compare(right)
+			}
+			if result != 0 {
+				return result
+			}
+		}
+	}
+	return 0
+}
+
+func (l *versionItems) isNull() bool {
+	return len(l.items) == 0
+}
+
+// normalize drops the insignificant zeros, release qualifiers and empty lists from the end of the list, looking past
+// non-empty lists.
+func (l *versionItems) normalize() {
+	for i := len(l.items) - 1; i >= 0; i-- {
+		if l.items[i].isNull() {
+			l.items = append(l.items[:i], l.items[i+1:]...)
+		} else if _, ok := l.items[i].(*versionItems); !ok {
+			break
+		}
+	}
+}
+
+// SortVersions sorts versions in ascending Maven order.
+func SortVersions(versions []string) {
+	parsed := make([]Version, len(versions))
+	for i, s := range versions {
+		parsed[i] = ParseVersion(s)
+	}
+	sort.Stable(byVersion{versions, parsed})
+}
+
+type byVersion struct {
+	versions []string
+	parsed   []Version
+}
+
+func (b byVersion) Len() int           { return len(b.versions) }
+func (b byVersion) Less(i, j int) bool { return b.parsed[i].Compare(b.parsed[j]) < 0 }
+func (b byVersion) Swap(i, j int) {
+	b.versions[i], b.versions[j] = b.versions[j], b.versions[i]
+	b.parsed[i], b.parsed[j] = b.parsed[j], b.parsed[i]
+}
+
+// VersionRange is a Maven version requirement, such as [1.2,2.0) or (,1.0],[1.2,).
+type VersionRange struct {
+	// Recommended is the version of a requirement that is a bare version, such as 1.0.  As in Maven, every version
+	// satisfies such a requirement; use [1.0] to require exactly 1.0.
+	Recommended *Version
+	// Restrictions are the ranges a version must fall into one of, in ascending order.  Empty for a bare version.
+	Restrictions []Restriction
+}
+
+// Restriction is a range of versions.  A nil bound leaves the range open on that side.
+type Restriction struct {
+	Lower          *Version
+	LowerInclusive bool
+	Upper          *Version
+	UpperInclusive bool
+}
+
+// ParseVersionRange parses a version requirement as Maven does: a bare version, or one or more comma-separated ranges in
+// which [ and ] include a bound and ( and ) exclude it.  A range given as a single version, such as [1.0], must include it.
+// The ranges may not overlap and must be in ascending order.
+func ParseVersionRange(spec string) (VersionRange, error) {
+	var r VersionRange
+	var upper *Version
+	process := strings.TrimSpace(spec)
+	for strings.HasPrefix(process, "[") || strings.HasPrefix(process, "(") {
+		end := strings.IndexAny(process, ")]")
+		if end < 0 {
+			return VersionRange{}, fmt.Errorf("ParseVersionRange(): unbounded range: %s", spec)
+		}
+		restriction, err := parseRestriction(process[:end+1])
+		if err != nil {
+			return VersionRange{}, fmt.Errorf("ParseVersionRange(): %v: %s", err, spec)
+		}
+		if len(r.Restrictions) > 0 && (upper == nil || restriction.Lower == nil || restriction.Lower.Compare(*upper) < 0) {
+			return VersionRange{}, fmt.Errorf("ParseVersionRange(): ranges overlap: %s", spec)
+		}
+		r.Restrictions = append(r.Restrictions, restriction)
+		upper = restriction.Upper
+
+		process = strings.TrimSpace(process[end+1:])
+		if strings.HasPrefix(process, ",") {
+			process = strings.TrimSpace(process[1:])
+		}
+	}
+	if process != "" {
+		if len(r.Restrictions) > 0 {
+			return VersionRange{}, fmt.Errorf("ParseVersionRange(): only ranges are allowed in a set of ranges: %s", spec)
+		}
+		v := ParseVersion(process)
+		r.Recommended = &v
+	}
+	if r.Recommended == nil && len(r.Restrictions) == 0 {
+		return VersionRange{}, fmt.Errorf("ParseVersionRange(): empty version requirement")
+	}
+	return r, nil
+}
+
+func parseRestriction(spec string) (Restriction, error) {
+	r := Restriction{LowerInclusive: strings.HasPrefix(spec, "["), UpperInclusive: strings.HasSuffix(spec, "]")}
+	process := strings.TrimSpace(spec[1 : len(spec)-1])
+	comma := strings.Index(process, ",")
+	if comma < 0 {
+		if !r.LowerInclusive || !r.UpperInclusive {
+			return Restriction{}, fmt.Errorf("single version must be surrounded by []")
+		}
+		if process == "" {
+			return Restriction{}, fmt.Errorf("empty range")
+		}
+		v := ParseVersion(process)
+		r.Lower, r.Upper = &v, &v
+		return r, nil
+	}
+
+	lower, upper := strings.TrimSpace(process[:comma]), strings.TrimSpace(process[comma+1:])
+	if strings.Contains(upper, ",") {
+		return Restriction{}, fmt.Errorf("invalid range %s", spec)
+	}
+	if lower != "" {
+		v := ParseVersion(lower)
+		r.Lower = &v
+	}
+	if upper != "" {
+		v := ParseVersion(upper)
+		r.Upper = &v
+	}
+	if r.Lower != nil && r.Upper != nil {
+		switch c := r.Upper.Compare(*r.Lower); {
+		case c < 0:
+			return Restriction{}, fmt.Errorf("range defies version ordering %s", spec)
+		case c == 0 && !(r.LowerInclusive && r.UpperInclusive):
+			return Restriction{}, fmt.Errorf("range cannot have identical boundaries %s", spec)
+		}
+	}
+	return r, nil
+}
+
+// Contains reports whether v falls into the restriction.
+func (r Restriction) Contains(v Version) bool {
+	if r.Lower != nil {
+		if c := r.Lower.Compare(v); c > 0 || (c == 0 && !r.LowerInclusive) {
+			return false
+		}
+	}
+	if r.Upper != nil {
+		if c := r.Upper.Compare(v); c < 0 || (c == 0 && !r.UpperInclusive) {
+			return false
+		}
+	}
+	return true
+}
+
+// Contains reports whether v satisfies the requirement.
+func (r VersionRange) Contains(v Version) bool {
+	if len(r.Restrictions) == 0 {
+		return true
+	}
+	for _, restriction := range r.Restrictions {
+		if restriction.Contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the versions that satisfy the requirement, in their original order.
+func (r VersionRange) Filter(versions []string) []string {
+	var matched []string
+	for _, s := range versions {
+		if r.Contains(ParseVersion(s)) {
+			matched = append(matched, s)
+		}
+	}
+	return matched
+}
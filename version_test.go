@@ -0,0 +1,137 @@
+package maventools
+
+import (
+	"strings"
+	"testing"
+)
+
+// checkVersionOrder checks that each of versions orders before all those after it.
+func checkVersionOrder(t *testing.T, versions []string) {
+	for i := range versions {
+		for j := i + 1; j < len(versions); j++ {
+			low, high := ParseVersion(versions[i]), ParseVersion(versions[j])
+			if low.Compare(high) >= 0 {
+				t.Fatalf("Want %s < %s but got %d\n", low, high, low.Compare(high))
+			}
+			if high.Compare(low) <= 0 {
+				t.Fatalf("Want %s > %s but got %d\n", high, low, high.Compare(low))
+			}
+		}
+	}
+}
+
+func TestVersionQualifierOrder(t *testing.T) {
+	checkVersionOrder(t, []string{
+		"1-alpha2snapshot", "1-alpha2", "1-alpha-123", "1-beta-2", "1-beta123", "1-m2", "1-m11", "1-rc", "1-cr2", "1-rc123",
+		"1-SNAPSHOT", "1", "1-sp", "1-sp2", "1-sp123", "1-abc", "1-def", "1-pom-1", "1-1-snapshot", "1-1", "1-2", "1-123",
+	})
+}
+
+func TestVersionNumberOrder(t *testing.T) {
+	checkVersionOrder(t, []string{
+		"2.0", "2-1", "2.0.a", "2.0.0.a", "2.0.2", "2.0.123", "2.1.0", "2.1-a", "2.1b", "2.1-c", "2.1-1", "2.1.0.1", "2.2",
+		"2.123", "11.a2", "11.a11", "11.b2", "11.b11", "11.m2", "11.m11", "11", "11.a", "11b", "11c", "11m",
+		"99999999999999999999999", "100000000000000000000000",
+	})
+}
+
+func TestVersionEquality(t *testing.T) {
+	for _, pair := range [][2]string{
+		{"1", "1.0"}, {"1", "1.0.0"}, {"1", "1-0"}, {"1.0", "1.0-0"}, {"1", "01"},
+		{"1a", "1-a"}, {"1a", "1.0.0-a"}, {"1.0x", "1-x"},
+		{"1ga", "1"}, {"1release", "1"}, {"1FINAL", "1"}, {"1cr", "1rc"},
+		{"1a1", "1-alpha-1"}, {"1b2", "1-beta-2"}, {"1m3", "1-MileStone-3"},
+		{"", "0"},
+	} {
+		a, b := ParseVersion(pair[0]), ParseVersion(pair[1])
+		if a.Compare(b) != 0 || b.Compare(a) != 0 {
+			t.Fatalf("Want %q = %q but got %d\n", a, b, a.Compare(b))
+		}
+	}
+	var zero Version
+	if zero.Compare(ParseVersion("0")) != 0 {
+		t.Fatalf("Want the zero Version to equal 0\n")
+	}
+}
+
+func TestSortVersions(t *testing.T) {
+	versions := []string{"1.10", "1.2-SNAPSHOT", "1.2", "1.9.1", "1.2-rc-1", "1.0"}
+	SortVersions(versions)
+	if got := strings.Join(versions, ","); got != "1.0,1.2-rc-1,1.2-SNAPSHOT,1.2,1.9.1,1.10" {
+		t.Fatalf("Want 1.0,1.2-rc-1,1.2-SNAPSHOT,1.2,1.9.1,1.10 but got %s\n", got)
+	}
+}
+
+func TestVersionRange(t *testing.T) {
+	var tests = []struct {
+		spec string
+		in   []string
+		out  []string
+	}{
+		{"[1.2,2.0)", []string{"1.2", "1.2.1", "1.10", "2.0-SNAPSHOT", "2.0-rc-1"}, []string{"1.1", "1.2-SNAPSHOT", "2.0", "2.0.1"}},
+		{"(1.2,2.0]", []string{"1.2.1", "2.0", "2.0.0"}, []string{"1.2", "1.2.0", "2.0.1"}},
+		{"[1.0]", []string{"1.0", "1"}, []string{"1.0.1", "0.9"}},
+		{"(,1.0],[1.2,)", []string{"0.1", "1.0", "1.2", "3"}, []string{"1.1", "1.0.1"}},
+		{"[1.5,)", []string{"1.5", "99"}, []string{"1.5-SNAPSHOT", "1.4"}},
+		{"1.0", []string{"0.1", "1.0", "2.0"}, nil},
+	}
+	for _, test := range tests {
+		r, err := ParseVersionRange(test.spec)
+		if err != nil {
+			t.Fatalf("Expecting no error but got one: %v\n", err)
+		}
+		for _, s := range test.in {
+			if !r.Contains(ParseVersion(s)) {
+				t.Fatalf("Want %s in %s\n", s, test.spec)
+			}
+		}
+		for _, s := range test.out {
+			if r.Contains(ParseVersion(s)) {
+				t.Fatalf("Want %s not in %s\n", s, test.spec)
+			}
+		}
+	}
+
+	r, _ := ParseVersionRange("1.0")
+	if r.Recommended == nil || r.Recommended.String() != "1.0" {
+		t.Fatalf("Want a recommended 1.0 but got %+v\n", r)
+	}
+	r, _ = ParseVersionRange("[1.2,2.0)")
+	if got := r.Filter([]string{"1.0", "1.2", "1.5", "2.0"}); strings.Join(got, ",") != "1.2,1.5" {
+		t.Fatalf("Want 1.2,1.5 but got %v\n", got)
+	}
+}
+
+func TestParseVersionRangeErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"[1.0",
+		"(1.0)",
+		"[1.0)",
+		"[2.0,1.0]",
+		"(1.0,1.0]",
+		"[1.0,1.5],[1.2,2.0]",
+		"[1.0,),[2.0,)",
+		"[1.0,2.0],3.0",
+		"[1.0,2.0,3.0]",
+	} {
+		if _, err := ParseVersionRange(spec); err == nil {
+			t.Fatalf("Expecting an error for %q but did not get one\n", spec)
+		}
+	}
+}
+
+func TestMetadataHighestVersion(t *testing.T) {
+	m := Metadata{Versioning: &Versioning{Versions: []string{"1.9", "1.10-SNAPSHOT", "1.2", "2.0", "1.10-rc-1"}}}
+	r, _ := ParseVersionRange("[1.0,2.0)")
+	if got, ok := m.HighestVersion(r, false); !ok || got != "1.10-rc-1" {
+		t.Fatalf("Want 1.10-rc-1 but got %s\n", got)
+	}
+	if got, ok := m.HighestVersion(r, true); !ok || got != "1.10-SNAPSHOT" {
+		t.Fatalf("Want 1.10-SNAPSHOT but got %s\n", got)
+	}
+	r, _ = ParseVersionRange("[3.0,)")
+	if _, ok := m.HighestVersion(r, true); ok {
+		t.Fatalf("Want no version but got one\n")
+	}
+}